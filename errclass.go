@@ -0,0 +1,113 @@
+package genstrument
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorClass is how a wrapper records an error returned by the wrapped call,
+// as decided by the function of an errclass directive.
+type ErrorClass int
+
+const (
+	// ErrorClassFailed records the error as a failure of the call. It is the class of unclassified errors.
+	ErrorClassFailed ErrorClass = iota
+	// ErrorClassExpected records the call as successful, with the error as its ErrorAttribute,
+	// like a not found error of a lookup.
+	ErrorClassExpected
+	// ErrorClassCanceled records the call as canceled by its caller, like after context.Canceled.
+	ErrorClassCanceled
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassFailed:
+		return "failed"
+	case ErrorClassExpected:
+		return "expected"
+	case ErrorClassCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// ErrorAttribute is the key of the attribute an expected or canceled error is set on.
+const ErrorAttribute = "error"
+
+// CancelSpan is implemented by a Span that can record the cancellation of the traced call.
+type CancelSpan interface {
+	Span
+	EndCanceled(ctx context.Context, err error)
+}
+
+// EndClassified ends span after the traced call returned the non-nil err of the class.
+// A failed error ends it with EndError, and an expected error with EndSuccess after setting it as the ErrorAttribute.
+// A canceled error ends it with EndCanceled or, if span is not a CancelSpan, like an expected error.
+func EndClassified(ctx context.Context, span Span, err error, class ErrorClass) {
+	switch class {
+	case ErrorClassFailed:
+		span.EndError(err)
+		return
+	case ErrorClassCanceled:
+		if cs, ok := span.(CancelSpan); ok {
+			cs.EndCanceled(ctx, err)
+			return
+		}
+	}
+	span.Attribute(ErrorAttribute).Error(err)
+	span.EndSuccess(ctx)
+}
+
+// ClassifiedMeasurement is implemented by a Measurement that can record classified errors.
+type ClassifiedMeasurement interface {
+	Measurement
+	EndClassified(ctx context.Context, err error, class ErrorClass)
+}
+
+// EndMeasurement ends m after the measured call returned err of the class.
+// If m is not a ClassifiedMeasurement, it ends with err only if it failed, so that expected
+// and canceled errors are not counted as errors.
+func EndMeasurement(ctx context.Context, m Measurement, err error, class ErrorClass) {
+	if cm, ok := m.(ClassifiedMeasurement); ok && err != nil {
+		cm.EndClassified(ctx, err, class)
+		return
+	}
+	if class != ErrorClassFailed {
+		err = nil
+	}
+	m.End(ctx, err)
+}
+
+// ClassifyCanceled classifies context.Canceled errors as canceled, and other errors as failed.
+func ClassifyCanceled(err error) ErrorClass {
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+	return ErrorClassFailed
+}
+
+// ExpectedErrors returns a classifier of the errors matching one of targets with errors.Is as expected,
+// and other errors as failed.
+func ExpectedErrors(targets ...error) func(error) ErrorClass {
+	return func(err error) ErrorClass {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return ErrorClassExpected
+			}
+		}
+		return ErrorClassFailed
+	}
+}
+
+// ClassifyErrors returns a classifier returning the first class other than ErrorClassFailed
+// returned by the classifiers, in order.
+func ClassifyErrors(classifiers ...func(error) ErrorClass) func(error) ErrorClass {
+	return func(err error) ErrorClass {
+		for _, classify := range classifiers {
+			if class := classify(err); class != ErrorClassFailed {
+				return class
+			}
+		}
+		return ErrorClassFailed
+	}
+}
@@ -0,0 +1,58 @@
+package example
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// Level is an int with a String method; its underlying kind ranks before fmt.Stringer.
+type Level int
+
+func (l Level) String() string {
+	return fmt.Sprintf("level-%d", int(l))
+}
+
+// Tags is a named string slice.
+type Tags []string
+
+// Secret is a string setting a masked attribute; AttributeMarshaler ranks before its underlying kind.
+type Secret string
+
+// MarshalAttributes sets the length of the secret, not its value.
+func (s Secret) MarshalAttributes(setter genstrument.AttributeSetter) {
+	setter.Attribute("length").Int64(int64(len(s)))
+}
+
+// Token is a byte slice with a String method; fmt.Stringer ranks before slices.
+type Token []byte
+
+func (t Token) String() string {
+	return hex.EncodeToString(t)
+}
+
+// AutoSetterService sets attributes with the auto-setters of the runtime package.
+//
+// +genstrument:wrap
+// +genstrument:attr count count
+// +genstrument:attr level level
+// +genstrument:attr limit limit
+// +genstrument:attr at at
+// +genstrument:attr timeout timeout
+// +genstrument:attr payload payload
+// +genstrument:attr tags tags
+// +genstrument:attr ids ids
+// +genstrument:attr stringer s
+// +genstrument:attr deadline deadline
+// +genstrument:attr secret secret
+// +genstrument:attr token token
+type AutoSetterService interface {
+	Counts(ctx context.Context, count uint64, level Level, limit *int) error
+	Times(ctx context.Context, at time.Time, timeout time.Duration, deadline *time.Time) error
+	Slices(ctx context.Context, payload []byte, tags Tags, ids []int64) error
+	Stringer(ctx context.Context, s fmt.Stringer) error
+	Ranked(ctx context.Context, secret Secret, token Token) error
+}
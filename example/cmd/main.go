@@ -8,13 +8,13 @@ import (
 	"fmt"
 	"genstrument/example"
 	"genstrument/example/gen"
-	"genstrument/example/oteltracer"
 	"genstrument/example/types"
 	"genstrument/example/types/dot"
 	gopkg "genstrument/example/types/go-pkg"
 	"log"
 
 	"github.com/go-logr/stdr"
+	genstrumentotel "github.com/justenwalker/genstrument/otel"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -25,6 +25,20 @@ import (
 
 //go:generate go run github.com/justenwalker/genstrument/genstrument -input ../simple.go -output ../gen/simple.gen.go
 //go:generate go run github.com/justenwalker/genstrument/genstrument -input ../complex.go -output ../gen/complex.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../embedded.go -output ../gen/embedded.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../exprs.go -output ../gen/exprs.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../collide.go -output ../gen/collide.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../metrics.go -output ../gen/metrics.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../logging.go -output ../gen/logging.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../panics.go -output ../gen/panics.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../optional.go -output ../gen/optional.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../opformat.go -output ../gen/opformat.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../marshaler.go -output ../gen/marshaler.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../autosetters.go -output ../gen/autosetters.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../fields.go -output ../gen/fields.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../staticattrs.go -output ../gen/staticattrs.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../modes.go -output ../gen/modes.gen.go
+//go:generate go run github.com/justenwalker/genstrument/genstrument -input ../errclass.go -output ../gen/errclass.gen.go
 //go:generate go run github.com/justenwalker/genstrument/genstrument -input ../external/external.go -output ../external/external.gen.go
 
 var (
@@ -59,8 +73,6 @@ func main() {
 		log.Panic(err)
 	}
 
-	// Create a named tracer with package path as its name.
-	tracer := tp.Tracer("go.opentelemetry.io/otel/example/namedtracer")
 	ctx := context.Background()
 	defer func() { _ = tp.Shutdown(ctx) }()
 
@@ -68,9 +80,7 @@ func main() {
 	m1, _ := baggage.NewMemberRaw(string(barKey), "bar1")
 	b, _ := baggage.New(m0, m1)
 	ctx = baggage.ContextWithBaggage(ctx, b)
-	t := &oteltracer.Tracer{
-		Tracer: tracer,
-	}
+	t := genstrumentotel.NewTracer(tp)
 	svc := &Service{}
 	cc := gen.InstrumentComplexService(t, svc)
 	ss := gen.InstrumentSimpleService(t, svc)
@@ -0,0 +1,15 @@
+package example
+
+import (
+	"context"
+	"genstrument/example/types"
+	gotypes "go/types"
+)
+
+// CollidingService uses two different packages which are both named types.
+//
+// +genstrument:wrap
+type CollidingService interface {
+	// +genstrument:attr type myType types.MyTypeAttr
+	Check(ctx context.Context, pkg *gotypes.Package, myType types.MyType) (gotypes.Object, error)
+}
@@ -25,15 +25,17 @@ type GenericService[T any, PT cmp.Ordered] interface {
 // +genstrument:wrap
 // +genstrument:constructor Instrument
 // +genstrument:prefix instrumented
+// +genstrument:setter ServiceType ServiceTypeSetter
+// +genstrument:setter Name StringAttributeSetter
 type ComplexService interface {
 	FuncNoError(ctx context.Context)
 	// +genstrument:attr key1 str StringAttributeSetter
-	// +genstrument:attr key2 st ServiceTypeSetter
+	// +genstrument:attr key2 st
 	// +genstrument:attr result res0 AnyTypeSetter
 	// +genstrument:attr error err AnyTypeSetter
 	FuncArray(ctx context.Context, str string, st ServiceType) (res0 [32]byte, err error)
-	// +genstrument:attr key1 name StringAttributeSetter
-	// +genstrument:attr key2 st ServiceTypeSetter
+	// +genstrument:attr key1 name
+	// +genstrument:attr key2 st
 	FuncSlice(ctx context.Context, name Name, st ServiceType) ([]byte, error)
 	// +genstrument:op goPkg2
 	// +genstrument:attr key1 name gopkg.GoType2Attr
@@ -0,0 +1,29 @@
+package example
+
+import (
+	"context"
+	"io"
+)
+
+// Getter is embedded into EmbeddedService; its directives are honored by the wrapper.
+type Getter interface {
+	// +genstrument:op get
+	// +genstrument:attr id id
+	Get(ctx context.Context, id string) (string, error)
+}
+
+// Lister is a generic interface embedded through an instantiation.
+type Lister[T any] interface {
+	List(ctx context.Context, filter T) ([]T, error)
+}
+
+// EmbeddedService
+//
+// +genstrument:wrap
+type EmbeddedService interface {
+	io.Closer
+	Getter
+	Lister[Name]
+	// +genstrument:attr name name
+	Put(ctx context.Context, name Name) error
+}
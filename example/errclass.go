@@ -0,0 +1,35 @@
+package example
+
+import (
+	"context"
+	"errors"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// ErrOrderNotFound is returned when an order does not exist.
+var ErrOrderNotFound = errors.New("order not found")
+
+// ClassifyOrderErrors records missing orders as expected, and canceled calls as canceled.
+var ClassifyOrderErrors = genstrument.ClassifyErrors(genstrument.ClassifyCanceled, genstrument.ExpectedErrors(ErrOrderNotFound))
+
+// OrderLookup classifies its errors, so that missing orders and canceled calls are not recorded as failures.
+//
+// +genstrument:wrap
+// +genstrument:metrics
+// +genstrument:log
+// +genstrument:errclass ClassifyOrderErrors
+type OrderLookup interface {
+	// +genstrument:attr order.id id
+	Status(ctx context.Context, id string) (status string, err error)
+	// +genstrument:errclass genstrument.ClassifyCanceled
+	Cancel(ctx context.Context, id string) error
+}
+
+// LookupOrder returns the status of an order.
+//
+// +genstrument:wrap
+// +genstrument:errclass ClassifyOrderErrors
+func LookupOrder(ctx context.Context, id string) (string, error) {
+	return "", ErrOrderNotFound
+}
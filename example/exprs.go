@@ -0,0 +1,45 @@
+package example
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExprService uses the less common type expressions in its method signatures.
+//
+// +genstrument:wrap
+type ExprService interface {
+	// +genstrument:attr names names AnyTypeSetter
+	Variadic(ctx context.Context, prefix string, names ...string) error
+	Channels(ctx context.Context, in <-chan Name, out chan<- Pair[string, int], both chan (<-chan int)) error
+	Funcs(ctx context.Context, fn func(context.Context, ...string) (int, error)) (func() error, error)
+	Structs(ctx context.Context, opts struct {
+		Name  Name `json:"name"`
+		Count int
+	}) (Pair[Name, []ServiceType], error)
+	Interfaces(ctx context.Context, s interface {
+		fmt.Stringer
+		Name() (first, last string)
+	}, p *ServiceType) error
+}
+
+// VariadicFunction
+//
+// +genstrument:wrap
+// +genstrument:attr key1 a
+// +genstrument:attr key2 b
+func VariadicFunction(ctx context.Context, a, b string, pairs ...Pair[string, int]) (count int, err error) {
+	return len(pairs), nil
+}
+
+// ExprFunction
+//
+// +genstrument:wrap
+func ExprFunction(ctx context.Context, in <-chan Name, fn func(context.Context, ...string) (int, error), opts struct {
+	Name Name `json:"name"`
+}, s interface {
+	fmt.Stringer
+	Name() (first, last string)
+}, p *ServiceType) (chan (<-chan int), Pair[Name, []ServiceType], error) {
+	return nil, Pair[Name, []ServiceType]{}, nil
+}
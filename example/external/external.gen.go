@@ -12,9 +12,18 @@ import (
 )
 
 // InstrumentSimpleService adds APM traces around the wrapped example.SimpleService using the provided tracer.
-func InstrumentSimpleService(tracer genstrument.Tracer, wrapped example.SimpleService) example.SimpleService {
+func InstrumentSimpleService(tracer genstrument.Tracer, wrapped example.SimpleService, opts ...genstrument.WrapperOption) example.SimpleService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedSimpleService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
 	return &instrumentedSimpleService{
 		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
 		wrapped: wrapped,
 	}
 }
@@ -26,12 +35,34 @@ var _ example.SimpleService = *new(SimpleService)
 type instrumentedSimpleService struct {
 	wrapped example.SimpleService
 	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.SimpleService.
+func (w *instrumentedSimpleService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedSimpleService) genstrumentWrapper() *instrumentedSimpleService {
+	return w
 }
 
 func (w *instrumentedSimpleService) SayHello(ctx context.Context, message string) (result string, err error) {
+	if w.cfg.Disabled("SayHello") {
+		return w.wrapped.SayHello(ctx, message)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "goPkg2")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("goPkg2"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	result, err = w.wrapped.SayHello(ctx, message)
@@ -47,9 +78,18 @@ func (w *instrumentedSimpleService) SayHello(ctx context.Context, message string
 }
 
 // TraceGenericService adds APM traces around the wrapped example.GenericService using the provided tracer.
-func TraceGenericService[T any, PT cmp.Ordered](tracer genstrument.Tracer, wrapped example.GenericService[T, PT]) example.GenericService[T, PT] {
+func TraceGenericService[T any, PT cmp.Ordered](tracer genstrument.Tracer, wrapped example.GenericService[T, PT], opts ...genstrument.WrapperOption) example.GenericService[T, PT] {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *tracedGenericService[T, PT]
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
 	return &tracedGenericService[T, PT]{
 		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
 		wrapped: wrapped,
 	}
 }
@@ -63,12 +103,34 @@ func _typecheck_GenericService[T any, PT cmp.Ordered]() example.GenericService[T
 type tracedGenericService[T any, PT cmp.Ordered] struct {
 	wrapped example.GenericService[T, PT]
 	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.GenericService.
+func (w *tracedGenericService[T, PT]) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *tracedGenericService[T, PT]) genstrumentWrapper() *tracedGenericService[T, PT] {
+	return w
 }
 
 func (w *tracedGenericService[T, PT]) FuncIsGeneric(ctx context.Context, t T) (ret0 PT, err error) {
+	if w.cfg.Disabled("FuncIsGeneric") {
+		return w.wrapped.FuncIsGeneric(ctx, t)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "external.GenericService:FuncIsGeneric")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("external.GenericService:FuncIsGeneric"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncIsGeneric(ctx, t)
@@ -93,6 +155,14 @@ func TraceMyFunction(tr genstrument.Tracer) func(ctx context.Context, s example.
 		example.AnyTypeSetter(d1, span.Attribute("key2"))
 		example.AnyTypeSetter(d2, span.Attribute("key3"))
 		example.AnyTypeSetter(myType, span.Attribute("key4"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		ret0, err = example.MyFunction(ctx, s, d1, d2, myType)
@@ -118,6 +188,14 @@ func TraceGenericFunction[T ~string, PT *T, PTT cmp.Ordered](tr genstrument.Trac
 		example.AnyTypeSetter(tr0, span.Attribute("key2"))
 		example.AnyTypeSetter(pt, span.Attribute("key3"))
 		example.AnyTypeSetter(err, span.Attribute("key4"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		ret0, err1 = example.GenericFunction[T, PT, PTT](ctx, t, tr0, pt, err)
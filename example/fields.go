@@ -0,0 +1,65 @@
+package example
+
+import (
+	"context"
+	"net/http"
+)
+
+// Org is the organization of a User.
+type Org struct {
+	Name string
+}
+
+// User is the user making a Request.
+type User struct {
+	ID    string
+	First string
+	Last  string
+	Org   *Org
+}
+
+// DisplayName returns the full name of the user.
+func (u *User) DisplayName() string {
+	return u.First + " " + u.Last
+}
+
+// GetOrg returns the organization of the user, or nil.
+func (u *User) GetOrg() *Org {
+	return u.Org
+}
+
+// Meta is embedded in requests.
+type Meta struct {
+	RequestID string
+}
+
+// Request is handled by FieldService.
+type Request struct {
+	*Meta
+	User   *User
+	Header http.Header
+}
+
+// GetUser returns the user making the request, or nil.
+func (r *Request) GetUser() *User {
+	return r.User
+}
+
+// Response is the result of FieldService.
+type Response struct {
+	Status int
+}
+
+// FieldService sets attributes from fields of its arguments and results.
+//
+// +genstrument:wrap
+type FieldService interface {
+	// +genstrument:attr user.id req.User.ID
+	// +genstrument:attr user.org req.User.Org.Name
+	// +genstrument:attr user.name req.User.DisplayName()
+	// +genstrument:attr user.login req.GetUser().ID
+	// +genstrument:attr user.org.name req.GetUser().GetOrg().Name
+	// +genstrument:attr request.id req.RequestID
+	// +genstrument:attr status resp.Status
+	Handle(ctx context.Context, req *Request) (resp *Response, err error)
+}
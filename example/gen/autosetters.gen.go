@@ -0,0 +1,210 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"fmt"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+	"time"
+)
+
+// InstrumentAutoSetterService adds APM traces around the wrapped example.AutoSetterService using the provided tracer.
+func InstrumentAutoSetterService(tracer genstrument.Tracer, wrapped example.AutoSetterService, opts ...genstrument.WrapperOption) example.AutoSetterService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedAutoSetterService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedAutoSetterService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedAutoSetterService struct {
+	wrapped example.AutoSetterService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.AutoSetterService.
+func (w *instrumentedAutoSetterService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedAutoSetterService) genstrumentWrapper() *instrumentedAutoSetterService {
+	return w
+}
+
+func (w *instrumentedAutoSetterService) Counts(ctx context.Context, count uint64, level example.Level, limit *int) (err error) {
+	if w.cfg.Disabled("Counts") {
+		return w.wrapped.Counts(ctx, count, level, limit)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.AutoSetterService:Counts"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetUintAttribute(count, span.Attribute("count"))
+	genstrument.SetIntAttribute(level, span.Attribute("level"))
+	genstrument.SetIntPointerAttribute(limit, span.Attribute("limit"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Counts(ctx, count, level, limit)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedAutoSetterService) Times(ctx context.Context, at time.Time, timeout time.Duration, deadline *time.Time) (err error) {
+	if w.cfg.Disabled("Times") {
+		return w.wrapped.Times(ctx, at, timeout, deadline)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.AutoSetterService:Times"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetTimeAttribute(at, span.Attribute("at"))
+	genstrument.SetDurationAttribute(timeout, span.Attribute("timeout"))
+	genstrument.SetTimePointerAttribute(deadline, span.Attribute("deadline"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Times(ctx, at, timeout, deadline)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedAutoSetterService) Slices(ctx context.Context, payload []byte, tags example.Tags, ids []int64) (err error) {
+	if w.cfg.Disabled("Slices") {
+		return w.wrapped.Slices(ctx, payload, tags, ids)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.AutoSetterService:Slices"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetBytesAttribute(payload, span.Attribute("payload"))
+	genstrument.SetStringSliceAttribute(tags, span.Attribute("tags"))
+	genstrument.SetIntSliceAttribute(ids, span.Attribute("ids"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Slices(ctx, payload, tags, ids)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedAutoSetterService) Stringer(ctx context.Context, s fmt.Stringer) (err error) {
+	if w.cfg.Disabled("Stringer") {
+		return w.wrapped.Stringer(ctx, s)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.AutoSetterService:Stringer"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringerAttribute(s, span.Attribute("stringer"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Stringer(ctx, s)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedAutoSetterService) Ranked(ctx context.Context, secret example.Secret, token example.Token) (err error) {
+	if w.cfg.Disabled("Ranked") {
+		return w.wrapped.Ranked(ctx, secret, token)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.AutoSetterService:Ranked"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetMarshalerAttribute(secret, span.Attribute("secret"))
+	genstrument.SetStringerAttribute(token, span.Attribute("token"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Ranked(ctx, secret, token)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
@@ -0,0 +1,75 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	types1 "genstrument/example/types"
+	"github.com/justenwalker/genstrument"
+	"go/types"
+)
+
+// InstrumentCollidingService adds APM traces around the wrapped example.CollidingService using the provided tracer.
+func InstrumentCollidingService(tracer genstrument.Tracer, wrapped example.CollidingService, opts ...genstrument.WrapperOption) example.CollidingService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedCollidingService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedCollidingService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedCollidingService struct {
+	wrapped example.CollidingService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.CollidingService.
+func (w *instrumentedCollidingService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedCollidingService) genstrumentWrapper() *instrumentedCollidingService {
+	return w
+}
+
+func (w *instrumentedCollidingService) Check(ctx context.Context, pkg *types.Package, myType types1.MyType) (ret0 types.Object, err error) {
+	if w.cfg.Disabled("Check") {
+		return w.wrapped.Check(ctx, pkg, myType)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.CollidingService:Check"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	types1.MyTypeAttr(myType, span.Attribute("type"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Check(ctx, pkg, myType)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
@@ -13,9 +13,18 @@ import (
 )
 
 // TraceGenericService adds APM traces around the wrapped example.GenericService using the provided tracer.
-func TraceGenericService[T any, PT cmp.Ordered](tracer genstrument.Tracer, wrapped example.GenericService[T, PT]) example.GenericService[T, PT] {
+func TraceGenericService[T any, PT cmp.Ordered](tracer genstrument.Tracer, wrapped example.GenericService[T, PT], opts ...genstrument.WrapperOption) example.GenericService[T, PT] {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *tracedGenericService[T, PT]
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
 	return &tracedGenericService[T, PT]{
 		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
 		wrapped: wrapped,
 	}
 }
@@ -23,12 +32,34 @@ func TraceGenericService[T any, PT cmp.Ordered](tracer genstrument.Tracer, wrapp
 type tracedGenericService[T any, PT cmp.Ordered] struct {
 	wrapped example.GenericService[T, PT]
 	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.GenericService.
+func (w *tracedGenericService[T, PT]) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *tracedGenericService[T, PT]) genstrumentWrapper() *tracedGenericService[T, PT] {
+	return w
 }
 
 func (w *tracedGenericService[T, PT]) FuncIsGeneric(ctx context.Context, t T) (ret0 PT, err error) {
+	if w.cfg.Disabled("FuncIsGeneric") {
+		return w.wrapped.FuncIsGeneric(ctx, t)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "example.GenericService:FuncIsGeneric")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.GenericService:FuncIsGeneric"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncIsGeneric(ctx, t)
@@ -44,9 +75,18 @@ func (w *tracedGenericService[T, PT]) FuncIsGeneric(ctx context.Context, t T) (r
 }
 
 // InstrumentComplexService adds APM traces around the wrapped example.ComplexService using the provided tracer.
-func InstrumentComplexService(tracer genstrument.Tracer, wrapped example.ComplexService) example.ComplexService {
+func InstrumentComplexService(tracer genstrument.Tracer, wrapped example.ComplexService, opts ...genstrument.WrapperOption) example.ComplexService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedComplexService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
 	return &instrumentedComplexService{
 		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
 		wrapped: wrapped,
 	}
 }
@@ -54,12 +94,35 @@ func InstrumentComplexService(tracer genstrument.Tracer, wrapped example.Complex
 type instrumentedComplexService struct {
 	wrapped example.ComplexService
 	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.ComplexService.
+func (w *instrumentedComplexService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedComplexService) genstrumentWrapper() *instrumentedComplexService {
+	return w
 }
 
 func (w *instrumentedComplexService) FuncNoError(ctx context.Context) {
+	if w.cfg.Disabled("FuncNoError") {
+		w.wrapped.FuncNoError(ctx)
+		return
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "example.ComplexService:FuncNoError")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ComplexService:FuncNoError"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	w.wrapped.FuncNoError(ctx)
@@ -70,23 +133,35 @@ func (w *instrumentedComplexService) FuncNoError(ctx context.Context) {
 }
 
 func (w *instrumentedComplexService) FuncArray(ctx context.Context, str string, st example.ServiceType) (res0 [32]byte, err error) {
+	if w.cfg.Disabled("FuncArray") {
+		return w.wrapped.FuncArray(ctx, str, st)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "example.ComplexService:FuncArray")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ComplexService:FuncArray"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
 	example.StringAttributeSetter(str, span.Attribute("key1"))
 	example.ServiceTypeSetter(st, span.Attribute("key2"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	res0, err = w.wrapped.FuncArray(ctx, str, st)
 	// Finish Span with Error
 	if err != nil {
+		example.AnyTypeSetter(err, span.Attribute("error"))
 		span.EndError(err)
 		return
 	}
 	// Set Return Attributes
 	example.AnyTypeSetter(res0, span.Attribute("result"))
-	example.AnyTypeSetter(err, span.Attribute("error"))
 
 	// Finish Span with Success
 	span.EndSuccess(ctx)
@@ -94,12 +169,24 @@ func (w *instrumentedComplexService) FuncArray(ctx context.Context, str string,
 }
 
 func (w *instrumentedComplexService) FuncSlice(ctx context.Context, name example.Name, st example.ServiceType) (ret0 []byte, err error) {
+	if w.cfg.Disabled("FuncSlice") {
+		return w.wrapped.FuncSlice(ctx, name, st)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "example.ComplexService:FuncSlice")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ComplexService:FuncSlice"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
 	example.StringAttributeSetter(name, span.Attribute("key1"))
 	example.ServiceTypeSetter(st, span.Attribute("key2"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncSlice(ctx, name, st)
@@ -115,9 +202,21 @@ func (w *instrumentedComplexService) FuncSlice(ctx context.Context, name example
 }
 
 func (w *instrumentedComplexService) FuncGoPkg2(ctx context.Context, mt gopkg.GoType2) (ret0 bool, err error) {
+	if w.cfg.Disabled("FuncGoPkg2") {
+		return w.wrapped.FuncGoPkg2(ctx, mt)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "goPkg2")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("goPkg2"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncGoPkg2(ctx, mt)
@@ -133,11 +232,23 @@ func (w *instrumentedComplexService) FuncGoPkg2(ctx context.Context, mt gopkg.Go
 }
 
 func (w *instrumentedComplexService) FuncPackageType(ctx context.Context, myType types.MyType) (ret0 int64, err error) {
+	if w.cfg.Disabled("FuncPackageType") {
+		return w.wrapped.FuncPackageType(ctx, myType)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "packageType")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("packageType"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
 	types.MyTypeAttr(myType, span.Attribute("type"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncPackageType(ctx, myType)
@@ -153,13 +264,25 @@ func (w *instrumentedComplexService) FuncPackageType(ctx context.Context, myType
 }
 
 func (w *instrumentedComplexService) FuncDotTypes(ctx context.Context, name example.Name, d1 dot.Type1Dot, d2 dot.Type2Dot) (ret0 string, err error) {
+	if w.cfg.Disabled("FuncDotTypes") {
+		return w.wrapped.FuncDotTypes(ctx, name, d1, d2)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "dots")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("dots"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
-	genstrument.SetStringAttribute(name, span.Attribute("name"))
+	example.StringAttributeSetter(name, span.Attribute("name"))
 	dot.Type1Attr(d1, span.Attribute("dot1"))
 	dot.Type2Attr(d2, span.Attribute("dot2"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncDotTypes(ctx, name, d1, d2)
@@ -175,11 +298,23 @@ func (w *instrumentedComplexService) FuncDotTypes(ctx context.Context, name exam
 }
 
 func (w *instrumentedComplexService) FuncMyDupeType(ctx context.Context, myType types.MyType) (ret0 string, err error) {
+	if w.cfg.Disabled("FuncMyDupeType") {
+		return w.wrapped.FuncMyDupeType(ctx, myType)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "dupes")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("dupes"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
 	types.MyTypeAttr(myType, span.Attribute("mine"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	ret0, err = w.wrapped.FuncMyDupeType(ctx, myType)
@@ -204,6 +339,14 @@ func TraceMyFunction(tr genstrument.Tracer) func(ctx context.Context, s example.
 		example.AnyTypeSetter(d1, span.Attribute("key2"))
 		example.AnyTypeSetter(d2, span.Attribute("key3"))
 		example.AnyTypeSetter(myType, span.Attribute("key4"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		ret0, err = example.MyFunction(ctx, s, d1, d2, myType)
@@ -229,6 +372,14 @@ func TraceGenericFunction[T ~string, PT *T, PTT cmp.Ordered](tr genstrument.Trac
 		example.AnyTypeSetter(tr0, span.Attribute("key2"))
 		example.AnyTypeSetter(pt, span.Attribute("key3"))
 		example.AnyTypeSetter(err, span.Attribute("key4"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		ret0, err1 = example.GenericFunction[T, PT, PTT](ctx, t, tr0, pt, err)
@@ -249,6 +400,14 @@ func ObserveGenericTypeConstraints[P any, S interface{ ~[]byte | string }, ES ~[
 	return func(ctx context.Context, p P, es ES, e E, c C, o O) (ret0 S, err error) {
 		var span genstrument.Span
 		ctx, span = tr.StartSpan(ctx, "example:GenericTypeConstraints")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		ret0, err = example.GenericTypeConstraints[P, S, ES, E, C, O](ctx, p, es, e, c, o)
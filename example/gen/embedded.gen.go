@@ -0,0 +1,166 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentEmbeddedService adds APM traces around the wrapped example.EmbeddedService using the provided tracer.
+func InstrumentEmbeddedService(tracer genstrument.Tracer, wrapped example.EmbeddedService, opts ...genstrument.WrapperOption) example.EmbeddedService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedEmbeddedService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedEmbeddedService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedEmbeddedService struct {
+	wrapped example.EmbeddedService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.EmbeddedService.
+func (w *instrumentedEmbeddedService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedEmbeddedService) genstrumentWrapper() *instrumentedEmbeddedService {
+	return w
+}
+
+func (w *instrumentedEmbeddedService) Put(ctx context.Context, name example.Name) (err error) {
+	if w.cfg.Disabled("Put") {
+		return w.wrapped.Put(ctx, name)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.EmbeddedService:Put"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(name, span.Attribute("name"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Put(ctx, name)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedEmbeddedService) Close() (err error) {
+	if w.cfg.Disabled("Close") {
+		return w.wrapped.Close()
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx := context.Background()
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.EmbeddedService:Close"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Close()
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedEmbeddedService) Get(ctx context.Context, id string) (ret0 string, err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("get"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(id, span.Attribute("id"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Get(ctx, id)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedEmbeddedService) List(ctx context.Context, filter example.Name) (ret0 []example.Name, err error) {
+	if w.cfg.Disabled("List") {
+		return w.wrapped.List(ctx, filter)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.EmbeddedService:List"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.List(ctx, filter)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
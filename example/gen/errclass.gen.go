@@ -0,0 +1,176 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/slogtracer"
+	"log/slog"
+)
+
+// InstrumentOrderLookup adds APM traces, metrics and logs around the wrapped example.OrderLookup using the provided tracer, meter and logger.
+func InstrumentOrderLookup(tracer genstrument.Tracer, meter genstrument.Meter, logger *slog.Logger, wrapped example.OrderLookup, opts ...genstrument.WrapperOption) example.OrderLookup {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedOrderLookup
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) && genstrument.SameInstrument(prev.meter, meter) && prev.logger == logger {
+			return wrapped
+		}
+	}
+	return &instrumentedOrderLookup{
+		tracer:  tracer,
+		meter:   meter,
+		logger:  logger,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedOrderLookup struct {
+	wrapped example.OrderLookup
+	tracer  genstrument.Tracer
+	meter   genstrument.Meter
+	logger  *slog.Logger
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.OrderLookup.
+func (w *instrumentedOrderLookup) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedOrderLookup) genstrumentWrapper() *instrumentedOrderLookup {
+	return w
+}
+
+func (w *instrumentedOrderLookup) Status(ctx context.Context, id string) (status string, err error) {
+	if w.cfg.Disabled("Status") {
+		return w.wrapped.Status(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OrderLookup:Status"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(id, span.Attribute("order.id"))
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.OrderLookup:Status"))
+	w.cfg.SetAttributes(measurement)
+	// Start Log
+	logCall := slogtracer.NewCall(w.logger, w.cfg.OperationName("example.OrderLookup:Status"))
+	w.cfg.SetAttributes(logCall)
+	genstrument.SetStringAttribute(id, logCall.Attribute("order.id"))
+	logCall.Start(ctx)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			logCall.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	status, err = w.wrapped.Status(ctx, id)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = example.ClassifyOrderErrors(err)
+	}
+	// Finish Measurement
+	genstrument.EndMeasurement(ctx, measurement, err, errClass)
+	// Finish Log
+	logCall.EndClassified(ctx, err, errClass)
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedOrderLookup) Cancel(ctx context.Context, id string) (err error) {
+	if w.cfg.Disabled("Cancel") {
+		return w.wrapped.Cancel(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OrderLookup:Cancel"))
+	w.cfg.SetAttributes(span)
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.OrderLookup:Cancel"))
+	w.cfg.SetAttributes(measurement)
+	// Start Log
+	logCall := slogtracer.NewCall(w.logger, w.cfg.OperationName("example.OrderLookup:Cancel"))
+	w.cfg.SetAttributes(logCall)
+	logCall.Start(ctx)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			logCall.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Cancel(ctx, id)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = genstrument.ClassifyCanceled(err)
+	}
+	// Finish Measurement
+	genstrument.EndMeasurement(ctx, measurement, err, errClass)
+	// Finish Log
+	logCall.EndClassified(ctx, err, errClass)
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// TraceLookupOrder traces the given fn using the provided tracer tr.
+func TraceLookupOrder(tr genstrument.Tracer) func(ctx context.Context, id string) (ret0 string, err error) {
+	return func(ctx context.Context, id string) (ret0 string, err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:LookupOrder")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		ret0, err = example.LookupOrder(ctx, id)
+		var errClass genstrument.ErrorClass
+		if err != nil {
+			errClass = example.ClassifyOrderErrors(err)
+		}
+		// Finish Span with Error
+		if err != nil {
+			genstrument.EndClassified(ctx, span, err, errClass)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
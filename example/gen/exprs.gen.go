@@ -0,0 +1,269 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"fmt"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentExprService adds APM traces around the wrapped example.ExprService using the provided tracer.
+func InstrumentExprService(tracer genstrument.Tracer, wrapped example.ExprService, opts ...genstrument.WrapperOption) example.ExprService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedExprService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedExprService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedExprService struct {
+	wrapped example.ExprService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.ExprService.
+func (w *instrumentedExprService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedExprService) genstrumentWrapper() *instrumentedExprService {
+	return w
+}
+
+func (w *instrumentedExprService) Variadic(ctx context.Context, prefix string, names ...string) (err error) {
+	if w.cfg.Disabled("Variadic") {
+		return w.wrapped.Variadic(ctx, prefix, names...)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ExprService:Variadic"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	example.AnyTypeSetter(names, span.Attribute("names"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Variadic(ctx, prefix, names...)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedExprService) Channels(ctx context.Context, in <-chan example.Name, out chan<- example.Pair[string, int], both chan (<-chan int)) (err error) {
+	if w.cfg.Disabled("Channels") {
+		return w.wrapped.Channels(ctx, in, out, both)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ExprService:Channels"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Channels(ctx, in, out, both)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedExprService) Funcs(ctx context.Context, fn func(context.Context, ...string) (int, error)) (ret0 func() error, err error) {
+	if w.cfg.Disabled("Funcs") {
+		return w.wrapped.Funcs(ctx, fn)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ExprService:Funcs"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Funcs(ctx, fn)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedExprService) Structs(ctx context.Context, opts struct {
+	Name  example.Name "json:\"name\""
+	Count int
+}) (ret0 example.Pair[example.Name, []example.ServiceType], err error) {
+	if w.cfg.Disabled("Structs") {
+		return w.wrapped.Structs(ctx, opts)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ExprService:Structs"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Structs(ctx, opts)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedExprService) Interfaces(ctx context.Context, s interface {
+	Name() (first string, last string)
+	fmt.Stringer
+}, p *example.ServiceType) (err error) {
+	if w.cfg.Disabled("Interfaces") {
+		return w.wrapped.Interfaces(ctx, s, p)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ExprService:Interfaces"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Interfaces(ctx, s, p)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// TraceVariadicFunction traces the given fn using the provided tracer tr.
+func TraceVariadicFunction(tr genstrument.Tracer) func(ctx context.Context, a string, b string, pairs ...example.Pair[string, int]) (count int, err error) {
+	return func(ctx context.Context, a string, b string, pairs ...example.Pair[string, int]) (count int, err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:VariadicFunction")
+		// Set Input Attributes
+		genstrument.SetStringAttribute(a, span.Attribute("key1"))
+		genstrument.SetStringAttribute(b, span.Attribute("key2"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		count, err = example.VariadicFunction(ctx, a, b, pairs...)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
+
+// TraceExprFunction traces the given fn using the provided tracer tr.
+func TraceExprFunction(tr genstrument.Tracer) func(ctx context.Context, in <-chan example.Name, fn func(context.Context, ...string) (int, error), opts struct {
+	Name example.Name "json:\"name\""
+}, s interface {
+	Name() (first string, last string)
+	fmt.Stringer
+}, p *example.ServiceType) (ret0 chan (<-chan int), ret1 example.Pair[example.Name, []example.ServiceType], err error) {
+	return func(ctx context.Context, in <-chan example.Name, fn func(context.Context, ...string) (int, error), opts struct {
+		Name example.Name "json:\"name\""
+	}, s interface {
+		Name() (first string, last string)
+		fmt.Stringer
+	}, p *example.ServiceType) (ret0 chan (<-chan int), ret1 example.Pair[example.Name, []example.ServiceType], err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:ExprFunction")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		ret0, ret1, err = example.ExprFunction(ctx, in, fn, opts, s, p)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
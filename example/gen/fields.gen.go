@@ -0,0 +1,100 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentFieldService adds APM traces around the wrapped example.FieldService using the provided tracer.
+func InstrumentFieldService(tracer genstrument.Tracer, wrapped example.FieldService, opts ...genstrument.WrapperOption) example.FieldService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedFieldService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedFieldService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedFieldService struct {
+	wrapped example.FieldService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.FieldService.
+func (w *instrumentedFieldService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedFieldService) genstrumentWrapper() *instrumentedFieldService {
+	return w
+}
+
+func (w *instrumentedFieldService) Handle(ctx context.Context, req *example.Request) (resp *example.Response, err error) {
+	if w.cfg.Disabled("Handle") {
+		return w.wrapped.Handle(ctx, req)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.FieldService:Handle"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	if req != nil && req.User != nil {
+		genstrument.SetStringAttribute(req.User.ID, span.Attribute("user.id"))
+	}
+	if req != nil && req.User != nil && req.User.Org != nil {
+		genstrument.SetStringAttribute(req.User.Org.Name, span.Attribute("user.org"))
+	}
+	if req != nil && req.User != nil {
+		genstrument.SetStringAttribute(req.User.DisplayName(), span.Attribute("user.name"))
+	}
+	if req != nil {
+		if v := req.GetUser(); v != nil {
+			genstrument.SetStringAttribute(v.ID, span.Attribute("user.login"))
+		}
+	}
+	if req != nil {
+		if v := req.GetUser(); v != nil {
+			if v := v.GetOrg(); v != nil {
+				genstrument.SetStringAttribute(v.Name, span.Attribute("user.org.name"))
+			}
+		}
+	}
+	if req != nil && req.Meta != nil {
+		genstrument.SetStringAttribute(req.Meta.RequestID, span.Attribute("request.id"))
+	}
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	resp, err = w.wrapped.Handle(ctx, req)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+	// Set Return Attributes
+	if resp != nil {
+		genstrument.SetIntAttribute(resp.Status, span.Attribute("status"))
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
@@ -0,0 +1,382 @@
+package gen_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"genstrument/example"
+	"genstrument/example/gen"
+	"genstrument/example/tenancy"
+	"genstrument/example/types"
+
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/genstrumenttest"
+	"github.com/justenwalker/genstrument/slogtracer"
+)
+
+type complexService struct {
+	example.ComplexService
+	err error
+}
+
+func (s *complexService) FuncArray(ctx context.Context, str string, st example.ServiceType) ([32]byte, error) {
+	return [32]byte{}, s.err
+}
+
+type simpleService struct{}
+
+func (simpleService) SayHello(_ context.Context, message string) (string, error) {
+	return message + "!", nil
+}
+
+func TestInstrumentComplexService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	errFailed := errors.New("failed")
+	svc := gen.InstrumentComplexService(tracer, &complexService{err: errFailed})
+	_, _ = svc.FuncArray(context.Background(), "str1", example.ServiceType{FooBarBaz: "fbb"})
+	tracer.RequireSpan(t, "example.ComplexService:FuncArray").
+		HasAttr("key1", "str1").
+		HasAttr("key2.foobarbaz", "fbb").
+		NoAttr("result").
+		EndedWithErrorIs(errFailed)
+}
+
+func TestInstrumentSimpleService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentSimpleService(tracer, simpleService{})
+	ctx, parent := tracer.StartSpan(context.Background(), "parent")
+	_, _ = svc.SayHello(ctx, "hello")
+	parent.EndSuccess(ctx)
+	tracer.RequireSpan(t, "helloOp").
+		HasParent("parent").
+		HasAttr("message", "hello").
+		HasAttr("result", "hello!").
+		NoAttr("err").
+		EndedWithSuccess()
+}
+
+type recoveringService struct{}
+
+func (recoveringService) Do(context.Context, string) (int, error) {
+	panic("do failed")
+}
+
+func (recoveringService) MustDo(context.Context, string) int {
+	panic("must do failed")
+}
+
+func (recoveringService) Debug(context.Context, bool) error {
+	panic("debug failed")
+}
+
+func TestInstrumentRecoveringService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentRecoveringService(tracer, recoveringService{})
+	_, err := svc.Do(context.Background(), "name")
+	var panicErr *genstrument.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "do failed" || len(panicErr.Stack) == 0 {
+		t.Errorf("expected a PanicError with a stack, got %v", err)
+	}
+	tracer.RequireSpan(t, "example.RecoveringService:Do").EndedWithPanic()
+
+	func() {
+		defer func() {
+			if r := recover(); r != "must do failed" {
+				t.Errorf("expected the panic to be re-panicked, got %v", r)
+			}
+		}()
+		svc.MustDo(context.Background(), "name")
+	}()
+	tracer.RequireSpan(t, "example.RecoveringService:MustDo").EndedWithPanic()
+
+	err = svc.Debug(context.Background(), true)
+	if !errors.As(err, &panicErr) || panicErr.Value != "debug failed" || len(panicErr.Stack) == 0 {
+		t.Errorf("expected a PanicError with a stack, got %v", err)
+	}
+	tracer.RequireSpan(t, "example.RecoveringService:Debug").EndedWithPanic()
+}
+
+type closingService struct{ closed bool }
+
+func (s *closingService) Get(context.Context, string, io.Writer) error {
+	return nil
+}
+
+func (s *closingService) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestInstrumentOptionalService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	wrapped := &closingService{}
+	svc := gen.InstrumentOptionalService(tracer, wrapped)
+	if _, ok := svc.(example.HealthChecker); ok {
+		t.Errorf("expected the wrapper not to implement HealthChecker")
+	}
+	closer, ok := svc.(io.Closer)
+	if !ok {
+		t.Fatalf("expected the wrapper to implement io.Closer")
+	}
+	if err := closer.Close(); err != nil || !wrapped.closed {
+		t.Errorf("expected the wrapped service to be closed, got %v", err)
+	}
+	tracer.RequireSpan(t, "example.OptionalService:Close").EndedWithSuccess()
+}
+
+func TestUnwrap(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	wrapped := &closingService{}
+	svc := gen.InstrumentOptionalService(tracer, wrapped)
+	if got := genstrument.Unwrap(svc); got != example.OptionalService(wrapped) {
+		t.Errorf("expected Unwrap to return the wrapped service, got %v", got)
+	}
+	if again := gen.InstrumentOptionalService(tracer, svc); again != svc {
+		t.Errorf("expected a service instrumented with the same tracer not to be wrapped again")
+	}
+	other := gen.InstrumentOptionalService(genstrumenttest.NewTracer(), svc)
+	if other == svc {
+		t.Fatalf("expected a service instrumented with another tracer to be wrapped again")
+	}
+	if _, ok := other.(io.Closer); !ok {
+		t.Errorf("expected the outer wrapper to implement io.Closer")
+	}
+	if got := genstrument.Unwrap(other); got != example.OptionalService(wrapped) {
+		t.Errorf("expected Unwrap to remove every wrapper, got %v", got)
+	}
+
+	// a comparable tracer holding a value that is not comparable is never the same
+	tagged := taggedTracer{Tracer: tracer, tag: []string{"a"}}
+	if genstrument.SameInstrument(tagged, tagged) {
+		t.Errorf("expected a tracer holding a slice not to be the same as itself")
+	}
+	svc = gen.InstrumentOptionalService(tagged, wrapped)
+	if again := gen.InstrumentOptionalService(tagged, svc); again == svc {
+		t.Errorf("expected a service instrumented with a tracer holding a slice to be wrapped again")
+	}
+}
+
+// taggedTracer is comparable, but comparing it panics if its tag is not.
+type taggedTracer struct {
+	genstrument.Tracer
+	tag any
+}
+
+func TestWrapperOptions(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentSimpleService(tracer, simpleService{},
+		genstrument.WithOperationPrefix("v2."),
+		genstrument.WithAttribute("service.instance", "a"),
+	)
+	if _, err := svc.SayHello(context.Background(), "hi"); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "v2.helloOp").HasAttr("service.instance", "a")
+
+	tracer.Reset()
+	svc = gen.InstrumentSimpleService(tracer, simpleService{}, genstrument.WithoutMethods("SayHello"))
+	if got, err := svc.SayHello(context.Background(), "hi"); err != nil || got != "hi!" {
+		t.Fatalf("expected the wrapped result, got %q, %v", got, err)
+	}
+	if spans := tracer.Spans(); len(spans) != 0 {
+		t.Errorf("expected no spans for a disabled method, got %v", spans)
+	}
+}
+
+type marshalerService struct{}
+
+func (marshalerService) Update(context.Context, types.MyType, *types.MyType, types.Account, *types.Account) error {
+	return nil
+}
+
+func TestInstrumentMarshalerService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentMarshalerService(tracer, marshalerService{})
+	mt := types.MyType{Foo: "foo", Secret: "secret"}
+	if err := svc.Update(context.Background(), mt, &mt, types.Account{ID: "a1", Token: "t"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "example.MarshalerService:Update").
+		HasAttr("type.foo", "foo").
+		HasAttr("type.secret", "MASKED").
+		HasAttr("ref.foo", "foo").
+		HasAttr("account.id", "a1").
+		NoAttr("account.token").
+		NoAttr("owner.id")
+}
+
+type autoSetterService struct{}
+
+func (autoSetterService) Counts(context.Context, uint64, example.Level, *int) error { return nil }
+
+func (autoSetterService) Times(context.Context, time.Time, time.Duration, *time.Time) error {
+	return nil
+}
+
+func (autoSetterService) Slices(context.Context, []byte, example.Tags, []int64) error { return nil }
+
+func (autoSetterService) Stringer(context.Context, fmt.Stringer) error { return nil }
+
+func (autoSetterService) Ranked(context.Context, example.Secret, example.Token) error { return nil }
+
+func TestInstrumentAutoSetterService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentAutoSetterService(tracer, autoSetterService{})
+	ctx := context.Background()
+	_ = svc.Counts(ctx, math.MaxUint64, example.Level(2), nil)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	_ = svc.Times(ctx, at, 90*time.Second, nil)
+	_ = svc.Slices(ctx, []byte("hi"), example.Tags{"a", "b"}, []int64{1, 2})
+	_ = svc.Stringer(ctx, example.Level(3))
+	_ = svc.Ranked(ctx, example.Secret("hunter2"), example.Token{0xab})
+
+	tracer.RequireSpan(t, "example.AutoSetterService:Counts").
+		HasAttr("count", "18446744073709551615").
+		HasAttr("level", int64(2)).
+		NoAttr("limit")
+	tracer.RequireSpan(t, "example.AutoSetterService:Times").
+		HasAttr("at", "2024-01-02T03:04:05Z").
+		HasAttr("timeout", "1m30s").
+		NoAttr("deadline")
+	tracer.RequireSpan(t, "example.AutoSetterService:Slices").
+		HasAttr("payload", "aGk=").
+		HasAttr("tags", []string{"a", "b"}).
+		HasAttr("ids", []int64{1, 2})
+	tracer.RequireSpan(t, "example.AutoSetterService:Stringer").
+		HasAttr("stringer", "level-3")
+	tracer.RequireSpan(t, "example.AutoSetterService:Ranked").
+		HasAttr("secret.length", int64(7)).
+		NoAttr("secret").
+		HasAttr("token", "ab")
+}
+
+type fieldService struct{}
+
+func (fieldService) Handle(context.Context, *example.Request) (*example.Response, error) {
+	return &example.Response{Status: 200}, nil
+}
+
+func TestInstrumentFieldService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	svc := gen.InstrumentFieldService(tracer, fieldService{})
+	req := &example.Request{User: &example.User{ID: "u1", First: "Ada", Last: "Lovelace"}}
+	if _, err := svc.Handle(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "example.FieldService:Handle").
+		HasAttr("user.id", "u1").
+		HasAttr("user.name", "Ada Lovelace").
+		HasAttr("status", 200).
+		NoAttr("user.org").
+		NoAttr("request.id")
+
+	tracer.Reset()
+	if _, err := svc.Handle(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "example.FieldService:Handle").NoAttr("user.id")
+}
+
+func TestTracePurgeOrders(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	purge := gen.TracePurgeOrders(tracer)
+	if err := purge(tenancy.WithTenant(context.Background(), "t1")); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "example:PurgeOrders").
+		HasAttr("component", "orders").
+		HasAttr("tenant.id", "t1")
+
+	tracer.Reset()
+	if err := purge(context.Background()); !errors.Is(err, example.ErrNoTenant) {
+		t.Fatalf("expected ErrNoTenant, got %v", err)
+	}
+	tracer.RequireSpan(t, "example:PurgeOrders").
+		NoAttr("tenant.id").
+		EndedWithErrorIs(example.ErrNoTenant)
+}
+
+type nopMeter struct{}
+
+func (nopMeter) StartOperation(context.Context, string) genstrument.Measurement {
+	return nopMeasurement{&slogtracer.Attributes{}}
+}
+
+type nopMeasurement struct{ *slogtracer.Attributes }
+
+func (nopMeasurement) End(context.Context, error) {}
+
+type batchService struct{ err error }
+
+func (s batchService) Import(_ context.Context, _ string, items []string) (int, time.Duration, string, error) {
+	if s.err != nil {
+		return len(items) / 2, time.Minute, "", s.err
+	}
+	return len(items), 0, "abc", nil
+}
+
+func TestInstrumentBatchService(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	errThrottled := errors.New("throttled")
+	svc := gen.InstrumentBatchService(tracer, nopMeter{}, logger, batchService{err: errThrottled})
+	_, _, _, _ = svc.Import(context.Background(), "b1", []string{"a", "b", "c", "d"})
+	tracer.RequireSpan(t, "example.BatchService:Import").
+		HasAttr("batch.id", "b1").
+		HasAttr("imported", 2).
+		HasAttr("retry.after", "1m0s").
+		NoAttr("checksum").
+		EndedWithErrorIs(errThrottled)
+
+	tracer.Reset()
+	svc = gen.InstrumentBatchService(tracer, nopMeter{}, logger, batchService{})
+	if _, _, _, err := svc.Import(context.Background(), "b2", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	tracer.RequireSpan(t, "example.BatchService:Import").
+		HasAttr("imported", 1).
+		HasAttr("checksum", "abc").
+		NoAttr("batch.id").
+		NoAttr("retry.after").
+		EndedWithSuccess()
+}
+
+type orderLookup struct{ err error }
+
+func (o orderLookup) Status(context.Context, string) (string, error) { return "", o.err }
+
+func (o orderLookup) Cancel(context.Context, string) error { return o.err }
+
+func TestInstrumentOrderLookup(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	_, _ = gen.InstrumentOrderLookup(tracer, nopMeter{}, logger, orderLookup{err: example.ErrOrderNotFound}).Status(ctx, "o1")
+	tracer.RequireSpan(t, "example.OrderLookup:Status").
+		HasAttr(genstrument.ErrorAttribute, example.ErrOrderNotFound).
+		EndedWithSuccess()
+
+	tracer.Reset()
+	_ = gen.InstrumentOrderLookup(tracer, nopMeter{}, logger, orderLookup{err: context.Canceled}).Cancel(ctx, "o1")
+	tracer.RequireSpan(t, "example.OrderLookup:Cancel").EndedWithCancel()
+
+	tracer.Reset()
+	_ = gen.InstrumentOrderLookup(tracer, nopMeter{}, logger, orderLookup{err: example.ErrOrderNotFound}).Cancel(ctx, "o1")
+	tracer.RequireSpan(t, "example.OrderLookup:Cancel").EndedWithErrorIs(example.ErrOrderNotFound)
+}
+
+func TestTraceLookupOrder(t *testing.T) {
+	tracer := genstrumenttest.NewTracer()
+	if _, err := gen.TraceLookupOrder(tracer)(context.Background(), "o1"); !errors.Is(err, example.ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+	tracer.RequireSpan(t, "example:LookupOrder").
+		HasAttr(genstrument.ErrorAttribute, example.ErrOrderNotFound).
+		EndedWithSuccess()
+}
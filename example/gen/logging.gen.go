@@ -0,0 +1,181 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/slogtracer"
+	"log/slog"
+)
+
+// InstrumentLoggedService adds APM traces and logs around the wrapped example.LoggedService using the provided tracer and logger.
+func InstrumentLoggedService(tracer genstrument.Tracer, logger *slog.Logger, wrapped example.LoggedService, opts ...genstrument.WrapperOption) example.LoggedService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedLoggedService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) && prev.logger == logger {
+			return wrapped
+		}
+	}
+	return &instrumentedLoggedService{
+		tracer:  tracer,
+		logger:  logger,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedLoggedService struct {
+	wrapped example.LoggedService
+	tracer  genstrument.Tracer
+	logger  *slog.Logger
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.LoggedService.
+func (w *instrumentedLoggedService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedLoggedService) genstrumentWrapper() *instrumentedLoggedService {
+	return w
+}
+
+func (w *instrumentedLoggedService) Get(ctx context.Context, id string) (name string, err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.LoggedService:Get"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(id, span.Attribute("id"))
+	// Start Log
+	logCall := slogtracer.NewCall(w.logger, w.cfg.OperationName("example.LoggedService:Get"))
+	w.cfg.SetAttributes(logCall)
+	genstrument.SetStringAttribute(id, logCall.Attribute("id"))
+	logCall.Start(ctx)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			logCall.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	name, err = w.wrapped.Get(ctx, id)
+	// Finish Log
+	if err == nil {
+		genstrument.SetStringAttribute(name, logCall.Attribute("name"))
+	}
+	logCall.End(ctx, err)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+	// Set Return Attributes
+	genstrument.SetStringAttribute(name, span.Attribute("name"))
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentAuditedService adds metrics and logs around the wrapped example.AuditedService using the provided meter and logger.
+func InstrumentAuditedService(meter genstrument.Meter, logger *slog.Logger, wrapped example.AuditedService, opts ...genstrument.WrapperOption) example.AuditedService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedAuditedService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.meter, meter) && prev.logger == logger {
+			return wrapped
+		}
+	}
+	return &instrumentedAuditedService{
+		meter:   meter,
+		logger:  logger,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedAuditedService struct {
+	wrapped example.AuditedService
+	meter   genstrument.Meter
+	logger  *slog.Logger
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.AuditedService.
+func (w *instrumentedAuditedService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedAuditedService) genstrumentWrapper() *instrumentedAuditedService {
+	return w
+}
+
+func (w *instrumentedAuditedService) Delete(ctx context.Context, user string) (err error) {
+	if w.cfg.Disabled("Delete") {
+		return w.wrapped.Delete(ctx, user)
+	}
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.AuditedService:Delete"))
+	w.cfg.SetAttributes(measurement)
+	genstrument.SetStringAttribute(user, measurement.Attribute("user"))
+	// Start Log
+	logCall := slogtracer.NewCall(w.logger, w.cfg.OperationName("example.AuditedService:Delete"))
+	w.cfg.SetAttributes(logCall)
+	genstrument.SetStringAttribute(user, logCall.Attribute("user"))
+	logCall.Start(ctx)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			logCall.End(ctx, panicErr)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Delete(ctx, user)
+	// Finish Measurement
+	measurement.End(ctx, err)
+	// Finish Log
+	logCall.End(ctx, err)
+	return
+}
+
+// TraceLoggedFunction adds logs to the given fn using the provided logger lg.
+func TraceLoggedFunction(lg *slog.Logger) func(message string) {
+	return func(message string) {
+		ctx := context.Background()
+		// Start Log
+		logCall := slogtracer.NewCall(lg, "example:LoggedFunction")
+		genstrument.SetStringAttribute(message, logCall.Attribute("message"))
+		logCall.Start(ctx)
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				logCall.End(ctx, panicErr)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		example.LoggedFunction(message)
+		// Finish Log
+		logCall.End(ctx, nil)
+		return
+	}
+}
@@ -0,0 +1,77 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"genstrument/example/types"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentMarshalerService adds APM traces around the wrapped example.MarshalerService using the provided tracer.
+func InstrumentMarshalerService(tracer genstrument.Tracer, wrapped example.MarshalerService, opts ...genstrument.WrapperOption) example.MarshalerService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedMarshalerService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedMarshalerService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedMarshalerService struct {
+	wrapped example.MarshalerService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.MarshalerService.
+func (w *instrumentedMarshalerService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedMarshalerService) genstrumentWrapper() *instrumentedMarshalerService {
+	return w
+}
+
+func (w *instrumentedMarshalerService) Update(ctx context.Context, myType types.MyType, ref *types.MyType, account types.Account, owner *types.Account) (err error) {
+	if w.cfg.Disabled("Update") {
+		return w.wrapped.Update(ctx, myType, ref, account, owner)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.MarshalerService:Update"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetMarshalerAttribute(myType, span.Attribute("type"))
+	genstrument.SetMarshalerPointerAttribute(ref, span.Attribute("ref"))
+	genstrument.SetMarshalerAddrAttribute(account, span.Attribute("account"))
+	genstrument.SetMarshalerPointerAttribute(owner, span.Attribute("owner"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Update(ctx, myType, ref, account, owner)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
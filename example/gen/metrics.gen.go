@@ -0,0 +1,228 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentMetricsService adds APM traces and metrics around the wrapped example.MetricsService using the provided tracer and meter.
+func InstrumentMetricsService(tracer genstrument.Tracer, meter genstrument.Meter, wrapped example.MetricsService, opts ...genstrument.WrapperOption) example.MetricsService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedMetricsService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) && genstrument.SameInstrument(prev.meter, meter) {
+			return wrapped
+		}
+	}
+	return &instrumentedMetricsService{
+		tracer:  tracer,
+		meter:   meter,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedMetricsService struct {
+	wrapped example.MetricsService
+	tracer  genstrument.Tracer
+	meter   genstrument.Meter
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.MetricsService.
+func (w *instrumentedMetricsService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedMetricsService) genstrumentWrapper() *instrumentedMetricsService {
+	return w
+}
+
+func (w *instrumentedMetricsService) Lookup(ctx context.Context, region string, user string) (found bool, err error) {
+	if w.cfg.Disabled("Lookup") {
+		return w.wrapped.Lookup(ctx, region, user)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.MetricsService:Lookup"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(region, span.Attribute("region"))
+	genstrument.SetStringAttribute(user, span.Attribute("user"))
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.MetricsService:Lookup"))
+	w.cfg.SetAttributes(measurement)
+	genstrument.SetStringAttribute(region, measurement.Attribute("region"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	found, err = w.wrapped.Lookup(ctx, region, user)
+	// Finish Measurement
+	measurement.End(ctx, err)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedMetricsService) Ping(ctx context.Context) {
+	if w.cfg.Disabled("Ping") {
+		w.wrapped.Ping(ctx)
+		return
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.MetricsService:Ping"))
+	w.cfg.SetAttributes(span)
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.MetricsService:Ping"))
+	w.cfg.SetAttributes(measurement)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	w.wrapped.Ping(ctx)
+	// Finish Measurement
+	measurement.End(ctx, nil)
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentCounterService adds metrics around the wrapped example.CounterService using the provided meter.
+func InstrumentCounterService(meter genstrument.Meter, wrapped example.CounterService, opts ...genstrument.WrapperOption) example.CounterService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedCounterService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.meter, meter) {
+			return wrapped
+		}
+	}
+	return &instrumentedCounterService{
+		meter:   meter,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedCounterService struct {
+	wrapped example.CounterService
+	meter   genstrument.Meter
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.CounterService.
+func (w *instrumentedCounterService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedCounterService) genstrumentWrapper() *instrumentedCounterService {
+	return w
+}
+
+func (w *instrumentedCounterService) Increment(name string) (ret0 int) {
+	if w.cfg.Disabled("Increment") {
+		return w.wrapped.Increment(name)
+	}
+	ctx := context.Background()
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.CounterService:Increment"))
+	w.cfg.SetAttributes(measurement)
+	genstrument.SetStringAttribute(name, measurement.Attribute("name"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0 = w.wrapped.Increment(name)
+	// Finish Measurement
+	measurement.End(ctx, nil)
+	return
+}
+
+// TraceMeteredFunction adds metrics to the given fn using the provided meter mt.
+func TraceMeteredFunction(mt genstrument.Meter) func(ctx context.Context, status string) (err error) {
+	return func(ctx context.Context, status string) (err error) {
+		// Start Measurement
+		measurement := mt.StartOperation(ctx, "example:MeteredFunction")
+		genstrument.SetStringAttribute(status, measurement.Attribute("status"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				measurement.End(ctx, panicErr)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		err = example.MeteredFunction(ctx, status)
+		// Finish Measurement
+		measurement.End(ctx, err)
+		return
+	}
+}
+
+// TraceTracedMeteredFunction adds APM traces and metrics to the given fn using the provided tracer tr and meter mt.
+func TraceTracedMeteredFunction(tr genstrument.Tracer, mt genstrument.Meter) func(ctx context.Context) (ret0 int, err error) {
+	return func(ctx context.Context) (ret0 int, err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:TracedMeteredFunction")
+		// Start Measurement
+		measurement := mt.StartOperation(ctx, "example:TracedMeteredFunction")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				measurement.End(ctx, panicErr)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		ret0, err = example.TracedMeteredFunction(ctx)
+		// Finish Measurement
+		measurement.End(ctx, err)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
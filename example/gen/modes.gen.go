@@ -0,0 +1,105 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/slogtracer"
+	"log/slog"
+	"time"
+)
+
+// InstrumentBatchService adds APM traces, metrics and logs around the wrapped example.BatchService using the provided tracer, meter and logger.
+func InstrumentBatchService(tracer genstrument.Tracer, meter genstrument.Meter, logger *slog.Logger, wrapped example.BatchService, opts ...genstrument.WrapperOption) example.BatchService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedBatchService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) && genstrument.SameInstrument(prev.meter, meter) && prev.logger == logger {
+			return wrapped
+		}
+	}
+	return &instrumentedBatchService{
+		tracer:  tracer,
+		meter:   meter,
+		logger:  logger,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedBatchService struct {
+	wrapped example.BatchService
+	tracer  genstrument.Tracer
+	meter   genstrument.Meter
+	logger  *slog.Logger
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.BatchService.
+func (w *instrumentedBatchService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedBatchService) genstrumentWrapper() *instrumentedBatchService {
+	return w
+}
+
+func (w *instrumentedBatchService) Import(ctx context.Context, batchID string, items []string) (n int, retryAfter time.Duration, sum string, err error) {
+	if w.cfg.Disabled("Import") {
+		return w.wrapped.Import(ctx, batchID, items)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.BatchService:Import"))
+	w.cfg.SetAttributes(span)
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.BatchService:Import"))
+	w.cfg.SetAttributes(measurement)
+	// Start Log
+	logCall := slogtracer.NewCall(w.logger, w.cfg.OperationName("example.BatchService:Import"))
+	w.cfg.SetAttributes(logCall)
+	logCall.Start(ctx)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			logCall.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	n, retryAfter, sum, err = w.wrapped.Import(ctx, batchID, items)
+	// Finish Measurement
+	genstrument.SetIntAttribute(n, measurement.Attribute("imported"))
+	measurement.End(ctx, err)
+	// Finish Log
+	genstrument.SetIntAttribute(n, logCall.Attribute("imported"))
+	if err != nil {
+		genstrument.SetStringAttribute(batchID, logCall.Attribute("batch.id"))
+		genstrument.SetDurationAttribute(retryAfter, logCall.Attribute("retry.after"))
+	} else {
+		genstrument.SetStringAttribute(sum, logCall.Attribute("checksum"))
+	}
+	logCall.End(ctx, err)
+	// Set Result Attributes
+	genstrument.SetIntAttribute(n, span.Attribute("imported"))
+	// Finish Span with Error
+	if err != nil {
+		genstrument.SetStringAttribute(batchID, span.Attribute("batch.id"))
+		genstrument.SetDurationAttribute(retryAfter, span.Attribute("retry.after"))
+		span.EndError(err)
+		return
+	}
+	// Set Return Attributes
+	genstrument.SetStringAttribute(sum, span.Attribute("checksum"))
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
@@ -0,0 +1,191 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentOrderStore adds APM traces around the wrapped example.OrderStore using the provided tracer.
+func InstrumentOrderStore(tracer genstrument.Tracer, wrapped example.OrderStore, opts ...genstrument.WrapperOption) example.OrderStore {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedOrderStore
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedOrderStore{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedOrderStore struct {
+	wrapped example.OrderStore
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.OrderStore.
+func (w *instrumentedOrderStore) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedOrderStore) genstrumentWrapper() *instrumentedOrderStore {
+	return w
+}
+
+func (w *instrumentedOrderStore) Get(ctx context.Context, id string) (ret0 string, err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("svc.example/OrderStore.Get"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Get(ctx, id)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedOrderStore) Put(ctx context.Context, id string, order string) (err error) {
+	if w.cfg.Disabled("Put") {
+		return w.wrapped.Put(ctx, id, order)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("orders.put"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Put(ctx, id, order)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentOrderCache adds APM traces around the wrapped example.OrderCache using the provided tracer.
+func InstrumentOrderCache[K comparable, V any](tracer genstrument.Tracer, wrapped example.OrderCache[K, V], opts ...genstrument.WrapperOption) example.OrderCache[K, V] {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedOrderCache[K, V]
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedOrderCache[K, V]{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedOrderCache[K comparable, V any] struct {
+	wrapped example.OrderCache[K, V]
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.OrderCache.
+func (w *instrumentedOrderCache[K, V]) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedOrderCache[K, V]) genstrumentWrapper() *instrumentedOrderCache[K, V] {
+	return w
+}
+
+func (w *instrumentedOrderCache[K, V]) Load(ctx context.Context, key K) (ret0 V, err error) {
+	if w.cfg.Disabled("Load") {
+		return w.wrapped.Load(ctx, key)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("genstrument/example/OrderCache[K,V].load"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Load(ctx, key)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// TracePlaceOrder traces the given fn using the provided tracer tr.
+func TracePlaceOrder(tr genstrument.Tracer) func(ctx context.Context, id string) (err error) {
+	return func(ctx context.Context, id string) (err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "svc.example/PlaceOrder")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		err = example.PlaceOrder(ctx, id)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
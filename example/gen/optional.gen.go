@@ -0,0 +1,388 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+	"io"
+)
+
+// InstrumentOptionalService adds APM traces around the wrapped example.OptionalService using the provided tracer.
+func InstrumentOptionalService(tracer genstrument.Tracer, wrapped example.OptionalService, opts ...genstrument.WrapperOption) example.OptionalService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedOptionalService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	w := &instrumentedOptionalService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+	// Implement the optional interfaces implemented by wrapped
+	opt0, ok0 := wrapped.(io.Closer)
+	opt1, ok1 := wrapped.(example.HealthChecker)
+	switch {
+	case ok0 && ok1:
+		return struct {
+			*instrumentedOptionalService
+			*instrumentedOptionalServiceCloser
+			*instrumentedOptionalServiceHealthChecker
+		}{w, &instrumentedOptionalServiceCloser{tracer: tracer, cfg: w.cfg, wrapped: opt0}, &instrumentedOptionalServiceHealthChecker{tracer: tracer, cfg: w.cfg, wrapped: opt1}}
+	case ok0:
+		return struct {
+			*instrumentedOptionalService
+			*instrumentedOptionalServiceCloser
+		}{w, &instrumentedOptionalServiceCloser{tracer: tracer, cfg: w.cfg, wrapped: opt0}}
+	case ok1:
+		return struct {
+			*instrumentedOptionalService
+			*instrumentedOptionalServiceHealthChecker
+		}{w, &instrumentedOptionalServiceHealthChecker{tracer: tracer, cfg: w.cfg, wrapped: opt1}}
+	}
+	return w
+}
+
+type instrumentedOptionalService struct {
+	wrapped example.OptionalService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.OptionalService.
+func (w *instrumentedOptionalService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedOptionalService) genstrumentWrapper() *instrumentedOptionalService {
+	return w
+}
+
+func (w *instrumentedOptionalService) Get(ctx context.Context, key string, w0 io.Writer) (err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, key, w0)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OptionalService:Get"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Get(ctx, key, w0)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+type instrumentedOptionalServiceCloser struct {
+	wrapped io.Closer
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+func (w *instrumentedOptionalServiceCloser) Close() (err error) {
+	if w.cfg.Disabled("Close") {
+		return w.wrapped.Close()
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx := context.Background()
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OptionalService:Close"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Close()
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+type instrumentedOptionalServiceHealthChecker struct {
+	wrapped example.HealthChecker
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+func (w *instrumentedOptionalServiceHealthChecker) CheckHealth(ctx context.Context) (err error) {
+	if w.cfg.Disabled("CheckHealth") {
+		return w.wrapped.CheckHealth(ctx)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("health.check"))
+	w.cfg.SetAttributes(span)
+	span.Attribute("check.kind").String("liveness")
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.CheckHealth(ctx)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentClosingService adds APM traces around the wrapped example.ClosingService using the provided tracer.
+func InstrumentClosingService(tracer genstrument.Tracer, wrapped example.ClosingService, opts ...genstrument.WrapperOption) example.ClosingService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedClosingService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	w := &instrumentedClosingService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+	// Implement the optional interfaces implemented by wrapped
+	opt0, ok0 := wrapped.(io.Closer)
+	opt1, ok1 := wrapped.(example.Closer)
+	switch {
+	case ok0 && ok1:
+		return struct {
+			*instrumentedClosingService
+			*instrumentedClosingServiceCloser2
+			*instrumentedClosingServiceCloser3
+		}{w, &instrumentedClosingServiceCloser2{tracer: tracer, cfg: w.cfg, wrapped: opt0}, &instrumentedClosingServiceCloser3{tracer: tracer, cfg: w.cfg, wrapped: opt1}}
+	case ok0:
+		return struct {
+			*instrumentedClosingService
+			*instrumentedClosingServiceCloser2
+		}{w, &instrumentedClosingServiceCloser2{tracer: tracer, cfg: w.cfg, wrapped: opt0}}
+	case ok1:
+		return struct {
+			*instrumentedClosingService
+			*instrumentedClosingServiceCloser3
+		}{w, &instrumentedClosingServiceCloser3{tracer: tracer, cfg: w.cfg, wrapped: opt1}}
+	}
+	return w
+}
+
+type instrumentedClosingService struct {
+	wrapped example.ClosingService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.ClosingService.
+func (w *instrumentedClosingService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedClosingService) genstrumentWrapper() *instrumentedClosingService {
+	return w
+}
+
+func (w *instrumentedClosingService) Get(ctx context.Context, key string) (err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, key)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ClosingService:Get"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Get(ctx, key)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+type instrumentedClosingServiceCloser2 struct {
+	wrapped io.Closer
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+func (w *instrumentedClosingServiceCloser2) Close() (err error) {
+	if w.cfg.Disabled("Close") {
+		return w.wrapped.Close()
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx := context.Background()
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ClosingService:Close"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Close()
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+type instrumentedClosingServiceCloser3 struct {
+	wrapped example.Closer
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+func (w *instrumentedClosingServiceCloser3) CloseContext(ctx context.Context) (err error) {
+	if w.cfg.Disabled("CloseContext") {
+		return w.wrapped.CloseContext(ctx)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ClosingService:CloseContext"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.CloseContext(ctx)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentClosingServiceCloser adds APM traces around the wrapped example.ClosingServiceCloser using the provided tracer.
+func InstrumentClosingServiceCloser(tracer genstrument.Tracer, wrapped example.ClosingServiceCloser, opts ...genstrument.WrapperOption) example.ClosingServiceCloser {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedClosingServiceCloser
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedClosingServiceCloser{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedClosingServiceCloser struct {
+	wrapped example.ClosingServiceCloser
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.ClosingServiceCloser.
+func (w *instrumentedClosingServiceCloser) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedClosingServiceCloser) genstrumentWrapper() *instrumentedClosingServiceCloser {
+	return w
+}
+
+func (w *instrumentedClosingServiceCloser) Close() (err error) {
+	if w.cfg.Disabled("Close") {
+		return w.wrapped.Close()
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx := context.Background()
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.ClosingServiceCloser:Close"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Close()
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
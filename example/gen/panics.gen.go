@@ -0,0 +1,154 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentRecoveringService adds APM traces around the wrapped example.RecoveringService using the provided tracer.
+func InstrumentRecoveringService(tracer genstrument.Tracer, wrapped example.RecoveringService, opts ...genstrument.WrapperOption) example.RecoveringService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedRecoveringService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedRecoveringService{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedRecoveringService struct {
+	wrapped example.RecoveringService
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.RecoveringService.
+func (w *instrumentedRecoveringService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedRecoveringService) genstrumentWrapper() *instrumentedRecoveringService {
+	return w
+}
+
+func (w *instrumentedRecoveringService) Do(ctx context.Context, name string) (ret0 int, err error) {
+	if w.cfg.Disabled("Do") {
+		return w.wrapped.Do(ctx, name)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.RecoveringService:Do"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			err = panicErr
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Do(ctx, name)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedRecoveringService) MustDo(ctx context.Context, name string) (ret0 int) {
+	if w.cfg.Disabled("MustDo") {
+		return w.wrapped.MustDo(ctx, name)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.RecoveringService:MustDo"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0 = w.wrapped.MustDo(ctx, name)
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedRecoveringService) Debug(ctx context.Context, debug bool) (err error) {
+	if w.cfg.Disabled("Debug") {
+		return w.wrapped.Debug(ctx, debug)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.RecoveringService:Debug"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			err = panicErr
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Debug(ctx, debug)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// TraceRecoveringFunction traces the given fn using the provided tracer tr.
+func TraceRecoveringFunction(tr genstrument.Tracer) func(ctx context.Context) (err error) {
+	return func(ctx context.Context) (err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:RecoveringFunction")
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				err = panicErr
+			}
+		}()
+
+		// call Wrapped Function
+		err = example.RecoveringFunction(ctx)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
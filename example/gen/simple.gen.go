@@ -9,9 +9,18 @@ import (
 )
 
 // InstrumentSimpleService adds APM traces around the wrapped example.SimpleService using the provided tracer.
-func InstrumentSimpleService(tracer genstrument.Tracer, wrapped example.SimpleService) example.SimpleService {
+func InstrumentSimpleService(tracer genstrument.Tracer, wrapped example.SimpleService, opts ...genstrument.WrapperOption) example.SimpleService {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedSimpleService
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
 	return &instrumentedSimpleService{
 		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
 		wrapped: wrapped,
 	}
 }
@@ -19,14 +28,36 @@ func InstrumentSimpleService(tracer genstrument.Tracer, wrapped example.SimpleSe
 type instrumentedSimpleService struct {
 	wrapped example.SimpleService
 	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.SimpleService.
+func (w *instrumentedSimpleService) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedSimpleService) genstrumentWrapper() *instrumentedSimpleService {
+	return w
 }
 
 func (w *instrumentedSimpleService) SayHello(ctx context.Context, message string) (result string, err error) {
+	if w.cfg.Disabled("SayHello") {
+		return w.wrapped.SayHello(ctx, message)
+	}
 	// Start Span
 	var span genstrument.Span
-	ctx, span = w.tracer.StartSpan(ctx, "helloOp")
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("helloOp"))
+	w.cfg.SetAttributes(span)
 	// Set Input Attributes
 	genstrument.SetStringAttribute(message, span.Attribute("message"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
 
 	// call Wrapped Function
 	result, err = w.wrapped.SayHello(ctx, message)
@@ -37,7 +68,6 @@ func (w *instrumentedSimpleService) SayHello(ctx context.Context, message string
 	}
 	// Set Return Attributes
 	genstrument.SetStringAttribute(result, span.Attribute("result"))
-	genstrument.SetErrorAttribute(err, span.Attribute("err"))
 
 	// Finish Span with Success
 	span.EndSuccess(ctx)
@@ -52,6 +82,14 @@ func TraceSimpleFunction(tr genstrument.Tracer) func(message string) (result str
 		ctx, span = tr.StartSpan(ctx, "helloOp")
 		// Set Input Attributes
 		genstrument.SetStringAttribute(message, span.Attribute("message"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
 
 		// call Wrapped Function
 		result, err = example.SimpleFunction(message)
@@ -62,7 +100,6 @@ func TraceSimpleFunction(tr genstrument.Tracer) func(message string) (result str
 		}
 		// Set Return Attributes
 		genstrument.SetStringAttribute(result, span.Attribute("result"))
-		genstrument.SetErrorAttribute(err, span.Attribute("err"))
 
 		// Finish Span with Success
 		span.EndSuccess(ctx)
@@ -0,0 +1,170 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+	"genstrument/example"
+	"genstrument/example/tenancy"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentOrderRepository adds APM traces and metrics around the wrapped example.OrderRepository using the provided tracer and meter.
+func InstrumentOrderRepository(tracer genstrument.Tracer, meter genstrument.Meter, wrapped example.OrderRepository, opts ...genstrument.WrapperOption) example.OrderRepository {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface {
+		genstrumentWrapper() *instrumentedOrderRepository
+	}); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) && genstrument.SameInstrument(prev.meter, meter) {
+			return wrapped
+		}
+	}
+	return &instrumentedOrderRepository{
+		tracer:  tracer,
+		meter:   meter,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedOrderRepository struct {
+	wrapped example.OrderRepository
+	tracer  genstrument.Tracer
+	meter   genstrument.Meter
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped example.OrderRepository.
+func (w *instrumentedOrderRepository) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedOrderRepository) genstrumentWrapper() *instrumentedOrderRepository {
+	return w
+}
+
+func (w *instrumentedOrderRepository) Find(ctx context.Context, id string) (err error) {
+	if w.cfg.Disabled("Find") {
+		return w.wrapped.Find(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OrderRepository:Find"))
+	w.cfg.SetAttributes(span)
+	span.Attribute("db.system").String("postgres")
+	span.Attribute("component").String("orders")
+	span.Attribute("db.operation").String("select")
+	span.Attribute("db.max_rows").Int64(100)
+	if v, ok := tenancy.FromContext(ctx); ok {
+		genstrument.SetStringAttribute(v, span.Attribute("tenant.id"))
+	}
+	if v, ok := example.RequestDeadline(ctx); ok {
+		genstrument.SetTimeAttribute(v, span.Attribute("request.deadline"))
+	}
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.OrderRepository:Find"))
+	w.cfg.SetAttributes(measurement)
+	measurement.Attribute("db.system").String("postgres")
+	if v, ok := tenancy.FromContext(ctx); ok {
+		genstrument.SetStringAttribute(v, measurement.Attribute("tenant.id"))
+	}
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Find(ctx, id)
+	// Finish Measurement
+	measurement.End(ctx, err)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *instrumentedOrderRepository) Save(ctx context.Context, id string) (err error) {
+	if w.cfg.Disabled("Save") {
+		return w.wrapped.Save(ctx, id)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("example.OrderRepository:Save"))
+	w.cfg.SetAttributes(span)
+	span.Attribute("db.system").String("postgres")
+	span.Attribute("component").String("order-writer")
+	span.Attribute("db.retryable").Bool(true)
+	span.Attribute("db.sample_rate").Float64(0.5)
+	span.Attribute("db.version").String("15")
+	if v, ok := tenancy.FromContext(ctx); ok {
+		genstrument.SetStringAttribute(v, span.Attribute("tenant.id"))
+	}
+	// Start Measurement
+	measurement := w.meter.StartOperation(ctx, w.cfg.OperationName("example.OrderRepository:Save"))
+	w.cfg.SetAttributes(measurement)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			measurement.End(ctx, panicErr)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Save(ctx, id)
+	// Finish Measurement
+	measurement.End(ctx, err)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// TracePurgeOrders traces the given fn using the provided tracer tr.
+func TracePurgeOrders(tr genstrument.Tracer) func(ctx context.Context) (err error) {
+	return func(ctx context.Context) (err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "example:PurgeOrders")
+		span.Attribute("component").String("orders")
+		if v, ok := tenancy.FromContext(ctx); ok {
+			genstrument.SetStringAttribute(v, span.Attribute("tenant.id"))
+		}
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		err = example.PurgeOrders(ctx)
+		// Finish Span with Error
+		if err != nil {
+			span.EndError(err)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/go-logr/stdr v1.2.2
 	github.com/justenwalker/genstrument v0.0.0
 	github.com/justenwalker/genstrument/genstrument v0.0.0
+	github.com/justenwalker/genstrument/otel v0.0.0
 	go.opentelemetry.io/otel v1.30.0
 	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.30.0
 	go.opentelemetry.io/otel/sdk v1.30.0
@@ -25,3 +26,5 @@ require (
 replace github.com/justenwalker/genstrument => ../
 
 replace github.com/justenwalker/genstrument/genstrument => ../genstrument
+
+replace github.com/justenwalker/genstrument/otel => ../otel
@@ -0,0 +1,34 @@
+package example
+
+import (
+	"context"
+)
+
+// LoggedService logs calls in addition to spans.
+//
+// +genstrument:wrap
+// +genstrument:log
+type LoggedService interface {
+	// +genstrument:attr id id
+	// +genstrument:attr name name
+	Get(ctx context.Context, id string) (name string, err error)
+}
+
+// AuditedService records metrics and logs calls, without spans.
+//
+// +genstrument:wrap
+// +genstrument:metrics notrace
+// +genstrument:log
+type AuditedService interface {
+	// +genstrument:attr user user
+	// +genstrument:metrics user
+	Delete(ctx context.Context, user string) error
+}
+
+// LoggedFunction
+//
+// +genstrument:wrap
+// +genstrument:log notrace
+// +genstrument:attr message message
+func LoggedFunction(message string) {
+}
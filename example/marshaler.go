@@ -0,0 +1,18 @@
+package example
+
+import (
+	"context"
+
+	"genstrument/example/types"
+)
+
+// MarshalerService sets attributes from types implementing genstrument.AttributeMarshaler.
+//
+// +genstrument:wrap
+type MarshalerService interface {
+	// +genstrument:attr type myType
+	// +genstrument:attr ref ref
+	// +genstrument:attr account account
+	// +genstrument:attr owner owner
+	Update(ctx context.Context, myType types.MyType, ref *types.MyType, account types.Account, owner *types.Account) error
+}
@@ -0,0 +1,44 @@
+package example
+
+import (
+	"context"
+)
+
+// MetricsService records RED metrics in addition to spans.
+//
+// +genstrument:wrap
+// +genstrument:metrics
+type MetricsService interface {
+	// +genstrument:attr region region
+	// +genstrument:attr user user
+	// +genstrument:metrics region
+	Lookup(ctx context.Context, region string, user string) (found bool, err error)
+	Ping(ctx context.Context)
+}
+
+// CounterService only records metrics.
+//
+// +genstrument:wrap
+// +genstrument:metrics notrace
+type CounterService interface {
+	// +genstrument:attr name name
+	// +genstrument:metrics name
+	Increment(name string) int
+}
+
+// MeteredFunction
+//
+// +genstrument:wrap
+// +genstrument:metrics notrace status
+// +genstrument:attr status status
+func MeteredFunction(ctx context.Context, status string) error {
+	return nil
+}
+
+// TracedMeteredFunction
+//
+// +genstrument:wrap
+// +genstrument:metrics
+func TracedMeteredFunction(ctx context.Context) (int, error) {
+	return 0, nil
+}
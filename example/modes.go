@@ -0,0 +1,20 @@
+package example
+
+import (
+	"context"
+	"time"
+)
+
+// BatchService imports items in batches, recording how far a failed import got.
+//
+// +genstrument:wrap
+// +genstrument:metrics
+// +genstrument:log
+type BatchService interface {
+	// +genstrument:attr batch.id batchID on-error
+	// +genstrument:attr imported n always
+	// +genstrument:attr retry.after retryAfter on-error
+	// +genstrument:attr checksum sum
+	// +genstrument:metrics imported
+	Import(ctx context.Context, batchID string, items []string) (n int, retryAfter time.Duration, sum string, err error)
+}
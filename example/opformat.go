@@ -0,0 +1,31 @@
+// +genstrument:defaults
+// +genstrument:opformat svc.{{.Package}}/{{with .Interface}}{{.}}.{{end}}{{.Method}}
+package example
+
+import (
+	"context"
+)
+
+// OrderStore names its spans with the format of the file.
+//
+// +genstrument:wrap
+type OrderStore interface {
+	Get(ctx context.Context, id string) (string, error)
+	// +genstrument:op orders.put
+	Put(ctx context.Context, id string, order string) error
+}
+
+// OrderCache names its spans with its own format.
+//
+// +genstrument:wrap
+// +genstrument:opformat {{.PkgPath}}/{{.Interface}}[{{join .TypeParams ","}}].{{lower .Method}}
+type OrderCache[K comparable, V any] interface {
+	Load(ctx context.Context, key K) (V, error)
+}
+
+// PlaceOrder names its span with the format of the file.
+//
+// +genstrument:wrap
+func PlaceOrder(ctx context.Context, id string) error {
+	return nil
+}
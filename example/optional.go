@@ -0,0 +1,42 @@
+package example
+
+import (
+	"context"
+	"io"
+)
+
+// HealthChecker is implemented by services reporting their health.
+type HealthChecker interface {
+	// +genstrument:op health.check
+	// +genstrument:const check.kind liveness
+	CheckHealth(ctx context.Context) error
+}
+
+// OptionalService keeps the io.Closer and HealthChecker implementations of the wrapped service.
+//
+// +genstrument:wrap
+// +genstrument:optional io.Closer HealthChecker
+type OptionalService interface {
+	Get(ctx context.Context, key string, w io.Writer) error
+}
+
+// Closer is implemented by values closed with a context, and has the name of io.Closer.
+type Closer interface {
+	CloseContext(ctx context.Context) error
+}
+
+// ClosingService keeps io.Closer and Closer, whose wrapper types must not have the same name,
+// nor that of the wrapper of ClosingServiceCloser.
+//
+// +genstrument:wrap
+// +genstrument:optional io.Closer Closer
+type ClosingService interface {
+	Get(ctx context.Context, key string) error
+}
+
+// ClosingServiceCloser has the name of ClosingService followed by that of its optional interfaces.
+//
+// +genstrument:wrap
+type ClosingServiceCloser interface {
+	Close() error
+}
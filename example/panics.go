@@ -0,0 +1,25 @@
+package example
+
+import (
+	"context"
+)
+
+// RecoveringService returns panics of its methods as errors.
+//
+// +genstrument:wrap
+// +genstrument:recover
+type RecoveringService interface {
+	Do(ctx context.Context, name string) (int, error)
+	// re-panics, since it has no error result
+	MustDo(ctx context.Context, name string) int
+	// debug shadows the name of the runtime/debug package
+	Debug(ctx context.Context, debug bool) error
+}
+
+// RecoveringFunction
+//
+// +genstrument:wrap
+// +genstrument:recover
+func RecoveringFunction(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,9 @@
+// Package pkgmode demonstrates generating a whole package with the -package flag.
+//
+// +genstrument:defaults
+// +genstrument:prefix traced
+// +genstrument:funcprefix Trace
+// +genstrument:errclass classifyErrors
+package pkgmode
+
+//go:generate go run github.com/justenwalker/genstrument/genstrument -package .
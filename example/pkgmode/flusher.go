@@ -0,0 +1,11 @@
+package pkgmode
+
+import "context"
+
+// StoreFlusher flushes a Store. The name of its wrapper type is that of the Store wrapper
+// followed by the name of its optional Flusher interface, declared in another file.
+//
+// +genstrument:wrap
+type StoreFlusher interface {
+	FlushStore(ctx context.Context, store Store) error
+}
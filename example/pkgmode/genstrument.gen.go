@@ -0,0 +1,258 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package pkgmode
+
+import (
+	"context"
+	"github.com/justenwalker/genstrument"
+)
+
+// InstrumentStoreFlusher adds APM traces around the wrapped StoreFlusher using the provided tracer.
+func InstrumentStoreFlusher(tracer genstrument.Tracer, wrapped StoreFlusher, opts ...genstrument.WrapperOption) StoreFlusher {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface{ genstrumentWrapper() *tracedStoreFlusher }); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &tracedStoreFlusher{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type tracedStoreFlusher struct {
+	wrapped StoreFlusher
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped StoreFlusher.
+func (w *tracedStoreFlusher) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *tracedStoreFlusher) genstrumentWrapper() *tracedStoreFlusher {
+	return w
+}
+
+func (w *tracedStoreFlusher) FlushStore(ctx context.Context, store Store) (err error) {
+	if w.cfg.Disabled("FlushStore") {
+		return w.wrapped.FlushStore(ctx, store)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("pkgmode.StoreFlusher:FlushStore"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.FlushStore(ctx, store)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = classifyErrors(err)
+	}
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// InstrumentStore adds APM traces around the wrapped Store using the provided tracer.
+func InstrumentStore(tracer genstrument.Tracer, wrapped Store, opts ...genstrument.WrapperOption) Store {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface{ genstrumentWrapper() *tracedStore }); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	w := &tracedStore{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+	// Implement the optional interfaces implemented by wrapped
+	opt0, ok0 := wrapped.(Flusher)
+	switch {
+	case ok0:
+		return struct {
+			*tracedStore
+			*tracedStoreFlusher2
+		}{w, &tracedStoreFlusher2{tracer: tracer, cfg: w.cfg, wrapped: opt0}}
+	}
+	return w
+}
+
+type tracedStore struct {
+	wrapped Store
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped Store.
+func (w *tracedStore) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *tracedStore) genstrumentWrapper() *tracedStore {
+	return w
+}
+
+func (w *tracedStore) Get(ctx context.Context, key string) (ret0 []byte, err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, key)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("pkgmode.Store:Get"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(key, span.Attribute("key"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Get(ctx, key)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = classifyErrors(err)
+	}
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+func (w *tracedStore) Put(ctx context.Context, key string, value []byte) (err error) {
+	if w.cfg.Disabled("Put") {
+		return w.wrapped.Put(ctx, key, value)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("pkgmode.Store:Put"))
+	w.cfg.SetAttributes(span)
+	// Set Input Attributes
+	genstrument.SetStringAttribute(key, span.Attribute("key"))
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Put(ctx, key, value)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = classifyErrors(err)
+	}
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+type tracedStoreFlusher2 struct {
+	wrapped Flusher
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+func (w *tracedStoreFlusher2) Flush(ctx context.Context) (err error) {
+	if w.cfg.Disabled("Flush") {
+		return w.wrapped.Flush(ctx)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("store.flush"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := genstrument.NewPanicError(r)
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	err = w.wrapped.Flush(ctx)
+	var errClass genstrument.ErrorClass
+	if err != nil {
+		errClass = classifyErrors(err)
+	}
+	// Finish Span with Error
+	if err != nil {
+		genstrument.EndClassified(ctx, span, err, errClass)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
+
+// ObservePing traces the given fn using the provided tracer tr.
+func ObservePing(tr genstrument.Tracer) func(ctx context.Context, target string) (ok bool, err error) {
+	return func(ctx context.Context, target string) (ok bool, err error) {
+		var span genstrument.Span
+		ctx, span = tr.StartSpan(ctx, "pkgmode:Ping")
+		// Set Input Attributes
+		genstrument.SetStringAttribute(target, span.Attribute("target"))
+		// Finish on Panic
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := genstrument.NewPanicError(r)
+				genstrument.EndPanic(span, r, panicErr.Stack)
+				panic(r)
+			}
+		}()
+
+		// call Wrapped Function
+		ok, err = Ping(ctx, target)
+		var errClass genstrument.ErrorClass
+		if err != nil {
+			errClass = classifyErrors(err)
+		}
+		// Finish Span with Error
+		if err != nil {
+			genstrument.EndClassified(ctx, span, err, errClass)
+			return
+		}
+
+		// Finish Span with Success
+		span.EndSuccess(ctx)
+		return
+	}
+}
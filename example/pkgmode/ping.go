@@ -0,0 +1,13 @@
+// +genstrument:defaults
+// +genstrument:funcprefix Observe
+package pkgmode
+
+import "context"
+
+// Ping
+//
+// +genstrument:wrap
+// +genstrument:attr target target
+func Ping(ctx context.Context, target string) (ok bool, err error) {
+	return true, nil
+}
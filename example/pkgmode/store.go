@@ -0,0 +1,30 @@
+package pkgmode
+
+import (
+	"context"
+	"errors"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// ErrNotFound is returned by Get for a missing key.
+var ErrNotFound = errors.New("not found")
+
+// classifyErrors records missing keys as expected, and canceled calls as canceled.
+var classifyErrors = genstrument.ClassifyErrors(genstrument.ClassifyCanceled, genstrument.ExpectedErrors(ErrNotFound))
+
+// Flusher is implemented by stores buffering their writes.
+type Flusher interface {
+	// +genstrument:op store.flush
+	Flush(ctx context.Context) error
+}
+
+// Store
+//
+// +genstrument:wrap
+// +genstrument:attr key key
+// +genstrument:optional Flusher
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte) error
+}
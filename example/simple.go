@@ -11,7 +11,6 @@ type SimpleService interface {
 	// +genstrument:op helloOp
 	// +genstrument:attr message message
 	// +genstrument:attr result result
-	// +genstrument:attr err err
 	SayHello(ctx context.Context, message string) (result string, err error)
 }
 
@@ -22,7 +21,6 @@ type SimpleService interface {
 // +genstrument:op helloOp
 // +genstrument:attr message message
 // +genstrument:attr result result
-// +genstrument:attr err err
 func SimpleFunction(message string) (result string, err error) {
 	return message, nil
 }
@@ -0,0 +1,49 @@
+package example
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"genstrument/example/tenancy"
+)
+
+// RequestDeadline returns the deadline of the request, if it has one.
+func RequestDeadline(ctx context.Context) (time.Time, bool) {
+	return ctx.Deadline()
+}
+
+// OrderRepository sets static attributes, and attributes extracted from the context, on its spans.
+//
+// +genstrument:wrap
+// +genstrument:metrics
+// +genstrument:const db.system postgres
+// +genstrument:const component orders
+// +genstrument:ctxattr tenant.id tenancy.FromContext
+type OrderRepository interface {
+	// +genstrument:const db.operation select
+	// +genstrument:const db.max_rows 100
+	// +genstrument:ctxattr request.deadline RequestDeadline
+	// +genstrument:metrics db.system tenant.id
+	Find(ctx context.Context, id string) error
+	// +genstrument:const component order-writer
+	// +genstrument:const db.retryable true
+	// +genstrument:const db.sample_rate 0.5
+	// +genstrument:const db.version "15"
+	Save(ctx context.Context, id string) error
+}
+
+// ErrNoTenant is returned by PurgeOrders without a tenant.
+var ErrNoTenant = errors.New("no tenant")
+
+// PurgeOrders deletes the orders of the tenant.
+//
+// +genstrument:wrap
+// +genstrument:const component orders
+// +genstrument:ctxattr tenant.id tenancy.FromContext
+func PurgeOrders(ctx context.Context) error {
+	if _, ok := tenancy.FromContext(ctx); !ok {
+		return ErrNoTenant
+	}
+	return nil
+}
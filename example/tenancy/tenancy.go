@@ -0,0 +1,17 @@
+// Package tenancy carries the tenant of a request in its context.
+package tenancy
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant id.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant id of the context, if it has one.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
@@ -11,3 +11,8 @@ type Name string
 type ServiceType struct {
 	FooBarBaz string
 }
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
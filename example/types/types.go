@@ -8,8 +8,24 @@ type MyType struct {
 	Secret string
 }
 
-func MyTypeAttr(mt MyType, setter genstrument.AttributeSetter) {
+// MarshalAttributes sets foo and bar, masking the secret.
+func (mt MyType) MarshalAttributes(setter genstrument.AttributeSetter) {
 	setter.Attribute("foo").String(mt.Foo)
 	setter.Attribute("bar").String(mt.Bar)
 	setter.Attribute("secret").String("MASKED")
 }
+
+func MyTypeAttr(mt MyType, setter genstrument.AttributeSetter) {
+	mt.MarshalAttributes(setter)
+}
+
+// Account sets its own attributes with a pointer receiver.
+type Account struct {
+	ID    string
+	Token string
+}
+
+// MarshalAttributes sets the id, without the token.
+func (a *Account) MarshalAttributes(setter genstrument.AttributeSetter) {
+	setter.Attribute("id").String(a.ID)
+}
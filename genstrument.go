@@ -28,3 +28,12 @@ type Span interface {
 type Tracer interface {
 	StartSpan(ctx context.Context, operationName string) (context.Context, Span)
 }
+
+type Measurement interface {
+	Attribute(key string) AttributeSetter
+	End(ctx context.Context, err error)
+}
+
+type Meter interface {
+	StartOperation(ctx context.Context, operationName string) Measurement
+}
@@ -0,0 +1,34 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff compares the generated content with the output file on disk without modifying it.
+// It returns a unified diff from the file on disk to the generated content,
+// or an empty string when the file is up-to-date.
+func (r *Result) Diff() (string, error) {
+	current, err := os.ReadFile(r.OutputFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("read %s: %w", r.OutputFile, err)
+	}
+	if err == nil && string(current) == string(r.Content) {
+		return "", nil
+	}
+	from := r.OutputFile
+	if err != nil {
+		from = "/dev/null"
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(string(r.Content)),
+		FromFile: from,
+		ToFile:   r.OutputFile,
+		Context:  3,
+	})
+}
@@ -0,0 +1,480 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	commentPrefix = "+genstrument:"
+)
+
+func (l *loader) toFunctionConfig(cg *ast.CommentGroup) (cfg functionConfig, ok bool) {
+	comments := extractDocComments(cg)
+	if len(comments) == 0 {
+		return
+	}
+	cfg.AttributeFunctions = make(map[string]*attributeKeyFunc)
+	for _, comment := range comments {
+		if comment.Text == "wrap" {
+			ok = true
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "external ") {
+			var err error
+			cfg.ExternalType, err = l.parseSelectorExpr(strings.TrimPrefix(comment.Text, "external "))
+			if err != nil {
+				l.recordError(comment.Pos, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "prefix ") {
+			cfg.Prefix = strings.TrimPrefix(comment.Text, "prefix ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "attr ") {
+			l.parseAttr(comment, cfg.AttributeFunctions)
+			continue
+		}
+		if comment.Text == "metrics" || strings.HasPrefix(comment.Text, "metrics ") {
+			cfg.Metrics = parseMetrics(comment)
+			continue
+		}
+		if comment.Text == "log" || strings.HasPrefix(comment.Text, "log ") {
+			cfg.Log = l.parseLog(comment)
+			continue
+		}
+		if comment.Text == "recover" {
+			cfg.Recover = true
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "op ") {
+			cfg.OperationName = strings.TrimPrefix(comment.Text, "op ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "const ") {
+			if c, ok := l.parseConst(comment); ok {
+				cfg.Consts = append(cfg.Consts, c)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "ctxattr ") {
+			if ca := l.parseCtxAttr(comment); ca != nil {
+				cfg.ContextAttrs = append(cfg.ContextAttrs, ca)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "errclass ") {
+			cfg.ErrClass = l.parseErrClass(comment)
+			continue
+		}
+		l.recordError(comment.Pos, fmt.Errorf("unknown function comment: %s", comment.Text))
+	}
+	return
+}
+
+func (l *loader) toInterfaceConfig(cg *ast.CommentGroup) (cfg interfaceConfig, ok bool) {
+	comments := extractDocComments(cg)
+	if len(comments) == 0 {
+		return
+	}
+	for _, comment := range comments {
+		if comment.Text == "wrap" {
+			ok = true
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "prefix ") {
+			cfg.Prefix = strings.TrimPrefix(comment.Text, "prefix ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "external ") {
+			var err error
+			cfg.ExternalType, err = l.parseSelectorExpr(strings.TrimPrefix(comment.Text, "external "))
+			if err != nil {
+				l.recordError(comment.Pos, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "constructor ") {
+			cfg.ConstructorPrefix = strings.TrimPrefix(comment.Text, "constructor ")
+			continue
+		}
+		if comment.Text == "metrics" || strings.HasPrefix(comment.Text, "metrics ") {
+			cfg.Metrics = parseMetrics(comment)
+			if len(cfg.Metrics.Keys) > 0 {
+				l.recordError(comment.Pos, fmt.Errorf("metrics: attribute keys are selected on methods, not interfaces"))
+			}
+			continue
+		}
+		if comment.Text == "log" || strings.HasPrefix(comment.Text, "log ") {
+			cfg.Log = l.parseLog(comment)
+			continue
+		}
+		if comment.Text == "recover" {
+			cfg.Recover = true
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "attr ") {
+			if cfg.AttributeFunctions == nil {
+				cfg.AttributeFunctions = make(map[string]*attributeKeyFunc)
+			}
+			l.parseAttr(comment, cfg.AttributeFunctions)
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "opformat ") {
+			cfg.OperationFormat = l.parseOpFormatDirective(comment)
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "setter ") {
+			if sc := l.parseSetter(comment); sc != nil {
+				cfg.Setters = append(cfg.Setters, sc)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "const ") {
+			if c, ok := l.parseConst(comment); ok {
+				cfg.Consts = append(cfg.Consts, c)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "ctxattr ") {
+			if ca := l.parseCtxAttr(comment); ca != nil {
+				cfg.ContextAttrs = append(cfg.ContextAttrs, ca)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "optional ") {
+			for _, name := range strings.Fields(strings.TrimPrefix(comment.Text, "optional ")) {
+				cfg.Optional = append(cfg.Optional, optionalConfig{Type: parseObjectExpr(name), Pos: comment.Pos})
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "errclass ") {
+			cfg.ErrClass = l.parseErrClass(comment)
+			continue
+		}
+		l.recordError(comment.Pos, fmt.Errorf("unknown interface comment: %s", comment.Text))
+	}
+	return
+}
+
+// toFileConfig parses the defaults directive of the doc comment of a file, above its package clause,
+// and the directives following it.
+func (l *loader) toFileConfig(cg *ast.CommentGroup) (cfg fileConfig) {
+	comments := extractDocComments(cg)
+	if len(comments) == 0 {
+		return
+	}
+	if comments[0].Text != "defaults" {
+		l.recordError(comments[0].Pos, fmt.Errorf("package clause directives must follow a defaults directive"))
+		return
+	}
+	for _, comment := range comments[1:] {
+		if strings.HasPrefix(comment.Text, "prefix ") {
+			cfg.Prefix = strings.TrimPrefix(comment.Text, "prefix ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "funcprefix ") {
+			cfg.FunctionPrefix = strings.TrimPrefix(comment.Text, "funcprefix ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "constructor ") {
+			cfg.ConstructorPrefix = strings.TrimPrefix(comment.Text, "constructor ")
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "opformat ") {
+			cfg.OperationFormat = l.parseOpFormatDirective(comment)
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "attr ") {
+			if cfg.AttributeFunctions == nil {
+				cfg.AttributeFunctions = make(map[string]*attributeKeyFunc)
+			}
+			l.parseAttr(comment, cfg.AttributeFunctions)
+			continue
+		}
+		if comment.Text == "metrics" || strings.HasPrefix(comment.Text, "metrics ") {
+			cfg.Metrics = parseMetrics(comment)
+			if len(cfg.Metrics.Keys) > 0 {
+				l.recordError(comment.Pos, fmt.Errorf("metrics: attribute keys are selected on methods, not defaults"))
+			}
+			continue
+		}
+		if comment.Text == "log" || strings.HasPrefix(comment.Text, "log ") {
+			cfg.Log = l.parseLog(comment)
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "setter ") {
+			if sc := l.parseSetter(comment); sc != nil {
+				cfg.Setters = append(cfg.Setters, sc)
+			}
+			continue
+		}
+		if strings.HasPrefix(comment.Text, "errclass ") {
+			cfg.ErrClass = l.parseErrClass(comment)
+			continue
+		}
+		l.recordError(comment.Pos, fmt.Errorf("unknown defaults comment: %s", comment.Text))
+	}
+	return
+}
+
+// parseAttr parses an "attr <key> <arg> [SetterFunction] [always|on-success|on-error]" directive into attrs.
+func (l *loader) parseAttr(comment directive, attrs map[string]*attributeKeyFunc) {
+	keyargfun := strings.Fields(strings.TrimPrefix(comment.Text, "attr "))
+	var mode string
+	if n := len(keyargfun); n > 2 && isAttrMode(keyargfun[n-1]) {
+		mode = keyargfun[n-1]
+		keyargfun = keyargfun[:n-1]
+	}
+	switch len(keyargfun) {
+	case 2:
+	case 3:
+	default:
+		l.recordError(comment.Pos, fmt.Errorf("attr: expected 2 or 3 arguments, got %d", len(keyargfun)))
+		return
+	}
+	keyname := keyargfun[0]
+	argName := keyargfun[1]
+	arg, path, err := parseAttrPath(argName)
+	if err != nil {
+		l.recordError(comment.Pos, fmt.Errorf("attr: %w", err))
+		return
+	}
+	attrs[argName] = &attributeKeyFunc{
+		Key:   keyname,
+		Arg:   arg,
+		Path:  path,
+		Mode:  mode,
+		Pos:   comment.Pos,
+		Scope: l.scope,
+	}
+	if len(keyargfun) == 3 {
+		attrs[argName].Func = parseObjectExpr(keyargfun[2])
+	}
+}
+
+// isAttrMode reports whether s is the mode of an attr directive.
+func isAttrMode(s string) bool {
+	switch s {
+	case attrAlways, attrOnSuccess, attrOnError:
+		return true
+	}
+	return false
+}
+
+// parseAttrPath parses the argument of an attr directive: an argument name, or a field path like req.User.ID
+// selecting fields and calling methods without arguments, like req.GetUser().ID.
+func parseAttrPath(s string) (arg string, path []attrSelector, err error) {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid argument %q", s)
+	}
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return e.Name, path, nil
+		case *ast.SelectorExpr:
+			path = append(path, attrSelector{Name: e.Sel.Name})
+			expr = e.X
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok || len(e.Args) != 0 || e.Ellipsis.IsValid() {
+				return "", nil, fmt.Errorf("invalid argument %q: only methods without arguments can be called", s)
+			}
+			path = append(path, attrSelector{Name: sel.Sel.Name, Call: true})
+			expr = sel.X
+		default:
+			return "", nil, fmt.Errorf("invalid argument %q: expected an argument name or a field path like req.User.ID", s)
+		}
+	}
+}
+
+// parseSetter parses a "setter <TypeName> <SetterFunction>" directive.
+func (l *loader) parseSetter(comment directive) *setterConfig {
+	fields := strings.Fields(strings.TrimPrefix(comment.Text, "setter "))
+	if len(fields) != 2 {
+		l.recordError(comment.Pos, fmt.Errorf("setter: expected 2 arguments, got %d", len(fields)))
+		return nil
+	}
+	return &setterConfig{
+		Type:  parseObjectExpr(fields[0]),
+		Func:  parseObjectExpr(fields[1]),
+		Pos:   comment.Pos,
+		Scope: l.scope,
+	}
+}
+
+// parseConst parses a "const <key> <value>" directive.
+func (l *loader) parseConst(comment directive) (constAttr, bool) {
+	key, value, _ := strings.Cut(strings.TrimPrefix(comment.Text, "const "), " ")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if key == "" || value == "" {
+		l.recordError(comment.Pos, fmt.Errorf("const: expected a key and a value"))
+		return constAttr{}, false
+	}
+	c := constAttr{Key: key}
+	c.Method, c.Value = constValue(value)
+	return c, true
+}
+
+// intLiteral and floatLiteral match decimal numbers, without the leading zeros of values like zip codes.
+var (
+	intLiteral   = regexp.MustCompile(`^[-+]?(0|[1-9]\d*)$`)
+	floatLiteral = regexp.MustCompile(`^[-+]?((0|[1-9]\d*)(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+)
+
+// constValue returns the genstrument.AttributeSetter method and the Go literal of the value of a const directive.
+// true and false are bools, decimal integer and float literals are numbers, and anything else is a string,
+// which may be quoted to keep a string like "42". Numbers with a leading zero, like 02134, and integers
+// out of the range of int64 are strings.
+func constValue(v string) (method, literal string) {
+	switch {
+	case v == "true" || v == "false":
+		return "Bool", v
+	case strings.HasPrefix(v, `"`):
+		if s, err := strconv.Unquote(v); err == nil {
+			return "String", strconv.Quote(s)
+		}
+	}
+	if intLiteral.MatchString(v) {
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return "Int64", strconv.FormatInt(i, 10)
+		}
+		// An integer out of the range of int64 is kept as written rather than rounded to a float.
+		return "String", strconv.Quote(v)
+	}
+	if floatLiteral.MatchString(v) {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return "Float64", strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+	return "String", strconv.Quote(v)
+}
+
+// parseCtxAttr parses a "ctxattr <key> <ExtractorFunction> [SetterFunction]" directive.
+func (l *loader) parseCtxAttr(comment directive) *ctxAttr {
+	fields := strings.Fields(strings.TrimPrefix(comment.Text, "ctxattr "))
+	if len(fields) != 2 && len(fields) != 3 {
+		l.recordError(comment.Pos, fmt.Errorf("ctxattr: expected 2 or 3 arguments, got %d", len(fields)))
+		return nil
+	}
+	ca := &ctxAttr{
+		Key:   fields[0],
+		Func:  parseObjectExpr(fields[1]),
+		Pos:   comment.Pos,
+		Scope: l.scope,
+	}
+	if len(fields) == 3 {
+		ca.Setter = parseObjectExpr(fields[2])
+	}
+	return ca
+}
+
+// parseErrClass parses an "errclass <ClassifierFunction>" directive.
+func (l *loader) parseErrClass(comment directive) *errClass {
+	fields := strings.Fields(strings.TrimPrefix(comment.Text, "errclass "))
+	if len(fields) != 1 {
+		l.recordError(comment.Pos, fmt.Errorf("errclass: expected 1 argument, got %d", len(fields)))
+		return nil
+	}
+	return &errClass{
+		Func:  parseObjectExpr(fields[0]),
+		Pos:   comment.Pos,
+		Scope: l.scope,
+	}
+}
+
+// parseOpFormatDirective parses an "opformat <template>" directive.
+func (l *loader) parseOpFormatDirective(comment directive) *template.Template {
+	t, err := parseOpFormat(strings.TrimSpace(strings.TrimPrefix(comment.Text, "opformat ")))
+	if err != nil {
+		l.recordError(comment.Pos, fmt.Errorf("opformat: %w", err))
+		return nil
+	}
+	return t
+}
+
+// parseMetrics parses a "metrics [notrace] [key...]" directive.
+func parseMetrics(comment directive) *metricsConfig {
+	mc := &metricsConfig{Pos: comment.Pos}
+	fields := strings.Fields(strings.TrimPrefix(comment.Text, "metrics"))
+	if len(fields) > 0 && fields[0] == "notrace" {
+		mc.NoTrace = true
+		fields = fields[1:]
+	}
+	mc.Keys = fields
+	return mc
+}
+
+// parseLog parses a "log [notrace]" directive.
+func (l *loader) parseLog(comment directive) *logConfig {
+	lc := &logConfig{Pos: comment.Pos}
+	switch arg := strings.TrimSpace(strings.TrimPrefix(comment.Text, "log")); arg {
+	case "":
+	case "notrace":
+		lc.NoTrace = true
+	default:
+		l.recordError(comment.Pos, fmt.Errorf("log: unexpected argument %q", arg))
+	}
+	return lc
+}
+
+type directive struct {
+	Text string
+	Pos  token.Pos
+}
+
+func (l *loader) parseSelectorExpr(expr string) (*ast.SelectorExpr, error) {
+	pkgType := strings.SplitN(strings.TrimSpace(expr), ".", 2)
+	if len(pkgType) != 2 {
+		return nil, fmt.Errorf("invalid selector expression '%s': expected 'package.TypeName'", expr)
+	}
+	return &ast.SelectorExpr{
+		X:   &ast.Ident{Name: pkgType[0]},
+		Sel: &ast.Ident{Name: pkgType[1]},
+	}, nil
+}
+
+// parseObjectExpr parses the name of an object, like Name or pkg.Name.
+func parseObjectExpr(name string) ast.Expr {
+	name = strings.TrimSpace(name)
+	pkgName := strings.SplitN(name, ".", 2)
+	if len(pkgName) == 1 {
+		return &ast.Ident{Name: name}
+	}
+	return &ast.SelectorExpr{
+		X:   &ast.Ident{Name: pkgName[0]},
+		Sel: &ast.Ident{Name: pkgName[1]},
+	}
+}
+
+func extractDocComments(cg *ast.CommentGroup) []directive {
+	if cg == nil {
+		// only documented interfaces are considered
+		return nil
+	}
+	var comments []directive
+	for _, comment := range cg.List {
+		text := strings.TrimPrefix(comment.Text, "//")
+		text = strings.TrimSpace(text)
+		if !strings.HasPrefix(text, commentPrefix) {
+			continue // not a doc comment
+		}
+		text = strings.TrimPrefix(text, commentPrefix)
+		comments = append(comments, directive{
+			Text: text,
+			Pos:  comment.Pos(),
+		})
+	}
+	return comments
+}
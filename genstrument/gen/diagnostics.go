@@ -0,0 +1,40 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// Diagnostic is a problem found in a directive or declaration of the source being generated.
+type Diagnostic struct {
+	Pos token.Position
+	Err error
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d: %v", d.Pos.Filename, d.Pos.Line, d.Err)
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics are all the problems which prevented generation.
+// The error returned by Generate wraps Diagnostics when the source is invalid.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	msgs := make([]string, len(d))
+	for i, diag := range d {
+		msgs[i] = diag.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (d Diagnostics) err() error {
+	if len(d) == 0 {
+		return nil
+	}
+	return d
+}
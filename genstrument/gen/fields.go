@@ -0,0 +1,94 @@
+package gen
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+)
+
+// fieldAttrs returns the attr directives with a field path of the argument arg, in the order they are declared.
+func fieldAttrs(attrs map[string]*attributeKeyFunc, arg string) []*attributeKeyFunc {
+	var fields []*attributeKeyFunc
+	for _, attr := range attrs {
+		if attr.Arg == arg && len(attr.Path) > 0 && attr.Key != "" {
+			fields = append(fields, attr)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Pos < fields[j].Pos
+	})
+	return fields
+}
+
+// resolveAttrPath type-checks the field path of an attr directive on an argument of type t.
+// It returns the attribute, whose value and nil checks are set once the argument is named, and the type of the value.
+func (l *loader) resolveAttrPath(f *wrappedFunction, t types.Type, path []attrSelector, it *typeImporter) (fa TemplateFieldAttr, _ types.Type, _ error) {
+	var pkg *types.Package
+	if f.Object != nil {
+		pkg = f.Object.Pkg()
+	}
+	// Variables and the fields of pointers are addressable; results of method calls are not.
+	addressable := true
+	for _, sel := range path {
+		obj, index, indirect := types.LookupFieldOrMethod(t, addressable, pkg, sel.Name)
+		if obj == nil {
+			if indirect {
+				return fa, nil, fmt.Errorf("cannot call pointer method %s on %s", sel.Name, it.describeType(t))
+			}
+			return fa, nil, fmt.Errorf("%s has no field or method %s", it.describeType(t), sel.Name)
+		}
+		if !obj.Exported() && obj.Pkg() != nil && obj.Pkg().Path() != it.currentPackage {
+			return fa, nil, fmt.Errorf("%s.%s is not exported", it.describeType(t), sel.Name)
+		}
+		// Select the embedded fields of a promoted field or method explicitly, to check them for nil.
+		for _, i := range index[:len(index)-1] {
+			fa.addNilCheck(t)
+			if _, ok := t.Underlying().(*types.Pointer); ok {
+				addressable = true
+			}
+			field := structOf(t).Field(i)
+			fa.selectors = append(fa.selectors, field.Name())
+			t = field.Type()
+		}
+		fa.addNilCheck(t)
+		if _, ok := t.Underlying().(*types.Pointer); ok {
+			addressable = true
+		}
+		switch obj := obj.(type) {
+		case *types.Var:
+			if sel.Call {
+				return fa, nil, fmt.Errorf("%s is a field, not a method", sel.Name)
+			}
+			fa.selectors = append(fa.selectors, sel.Name)
+			t = obj.Type()
+		case *types.Func:
+			if !sel.Call {
+				return fa, nil, fmt.Errorf("%s is a method, call it with %s()", sel.Name, sel.Name)
+			}
+			sig := obj.Type().(*types.Signature)
+			if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+				return fa, nil, fmt.Errorf("method %s must have no parameters and a single result", sel.Name)
+			}
+			fa.selectors = append(fa.selectors, sel.Name+"()")
+			t = sig.Results().At(0).Type()
+			addressable = false
+		}
+	}
+	return fa, t, nil
+}
+
+// addNilCheck checks the value selected so far for nil, if its type t is a pointer or interface.
+func (fa *TemplateFieldAttr) addNilCheck(t types.Type) {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		fa.nilChecks = append(fa.nilChecks, len(fa.selectors))
+	}
+}
+
+// structOf returns the struct type of t, or of the type t points to.
+func structOf(t types.Type) *types.Struct {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	return t.Underlying().(*types.Struct)
+}
@@ -0,0 +1,734 @@
+// Package gen generates instrumentation wrappers for interfaces and functions annotated
+// with +genstrument: directives. The genstrument command is a thin wrapper around it.
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// Result is a generated output file.
+type Result struct {
+	OutputFile string
+	Content    []byte
+}
+
+// Config selects the source to generate and how.
+type Config struct {
+	// InputFile and OutputFile are the source file and destination used by Generate.
+	InputFile  string
+	OutputFile string
+	// Packages are the package patterns, like ./..., scanned by GeneratePackages.
+	Packages []string
+	Options  Options
+}
+
+// Options customize the generated code. Empty fields use their defaults.
+type Options struct {
+	// Package is the name of the output package.
+	// By default, it is the source package name when generating into the source package,
+	// or the name of the output directory otherwise.
+	Package string
+	// ConstructorPrefix is the default for the constructor directive. Defaults to "Instrument".
+	ConstructorPrefix string
+	// TypePrefix is the default prefix of wrapper types. Defaults to "instrumented".
+	TypePrefix string
+	// FunctionPrefix is the default prefix of wrapper functions. Defaults to "Trace".
+	FunctionPrefix string
+	// Header is added as a comment at the top of every generated file, like a license notice.
+	Header string
+	// BuildTags is a build constraint expression added to every generated file as a //go:build line.
+	BuildTags string
+	// Metrics records RED metrics with a genstrument.Meter, in addition to tracing,
+	// in every wrapper without its own metrics directive.
+	Metrics bool
+	// Log logs calls to a *slog.Logger, in addition to tracing,
+	// in every wrapper without its own log directive.
+	Log bool
+	// TemplateFiles are custom template files, parsed in order on top of the built-in templates.
+	// A file can redefine the named templates "type", "constructor", "method" and "function",
+	// or replace the whole output when it has content outside of {{define}} blocks.
+	// The templates are executed with TemplateData.
+	TemplateFiles []string
+	// Template is custom template text, parsed after TemplateFiles.
+	Template string
+	// OperationFormat is the default template of operation names, executed with OperationNameData.
+	// Defaults to "{{.Package}}:{{.Method}}" for functions and "{{.Package}}.{{.Interface}}:{{.Method}}" for methods.
+	// It is overridden by the opformat directive of a file or interface, and by the op directive.
+	OperationFormat string
+	// OutputPattern names the files written by GeneratePackages, relative to the package directory.
+	// If it contains {{base}}, one file is written for each source file with directives, with {{base}}
+	// replaced by the source file name without its extension. Otherwise, one file is written per package.
+	OutputPattern string
+	// Check is set when the results are only compared with the output files, like with the -check flag.
+	// Nothing is written then: the unformatted source of a format error is annotated in the error
+	// instead of being written to a temporary file.
+	Check bool
+}
+
+const (
+	defaultConstructorPrefix = "Instrument"
+	defaultTypePrefix        = "instrumented"
+	defaultFunctionPrefix    = "Trace"
+	defaultOutputPattern     = "genstrument.gen.go"
+	basePlaceholder          = "{{base}}"
+)
+
+func (o Options) withDefaults() Options {
+	if o.ConstructorPrefix == "" {
+		o.ConstructorPrefix = defaultConstructorPrefix
+	}
+	if o.TypePrefix == "" {
+		o.TypePrefix = defaultTypePrefix
+	}
+	if o.FunctionPrefix == "" {
+		o.FunctionPrefix = defaultFunctionPrefix
+	}
+	if o.OutputPattern == "" {
+		o.OutputPattern = defaultOutputPattern
+	}
+	return o
+}
+
+// Generate generates the wrappers declared in Config.InputFile for Config.OutputFile.
+// Nothing is written; use Result.WriteOutput to write the file.
+// Canceling ctx stops loading the packages.
+// When the source is invalid, the returned error wraps Diagnostics.
+func Generate(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.InputFile == "" || cfg.OutputFile == "" {
+		return nil, fmt.Errorf("an input and output file are required")
+	}
+	l, err := newLoader(ctx, cfg.Options.withDefaults())
+	if err != nil {
+		return nil, err
+	}
+	pf, err := l.loadInputFile(cfg.InputFile)
+	if err != nil {
+		return nil, fmt.Errorf("Load Input file '%s' Failed:\n%w", cfg.InputFile, err)
+	}
+	absOutput, err := filepath.Abs(filepath.Clean(cfg.OutputFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not get absolute path of %s: %w", cfg.OutputFile, err)
+	}
+	l.reserveTypeNames([]*parsedFile{pf})
+	return l.render([]*parsedFile{pf}, absOutput)
+}
+
+// GeneratePackages scans every non-generated file of the packages matching Config.Packages for directives
+// and generates the output files named by Options.OutputPattern in each package directory.
+// Packages without any directives produce no output. Canceling ctx stops loading the packages.
+// When the source is invalid, the returned error wraps Diagnostics.
+func GeneratePackages(ctx context.Context, cfg Config) ([]*Result, error) {
+	if len(cfg.Packages) == 0 {
+		return nil, fmt.Errorf("at least one package pattern is required")
+	}
+	opts := cfg.Options.withDefaults()
+	l, err := newLoader(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := l.loadPackages(cfg.Packages)
+	if err != nil {
+		return nil, err
+	}
+	var results []*Result
+	for _, pkg := range pkgs {
+		files, err := l.loadPackageFiles(pkg)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		l.reserveTypeNames(files)
+		groups := [][]*parsedFile{files}
+		if strings.Contains(opts.OutputPattern, basePlaceholder) {
+			groups = groups[:0]
+			for _, f := range files {
+				groups = append(groups, []*parsedFile{f})
+			}
+		}
+		for _, group := range groups {
+			outFile := outputFileName(group[0].Filename, opts.OutputPattern)
+			res, err := l.render(group, outFile)
+			if err != nil {
+				return nil, fmt.Errorf("package %s: %w", pkg.PkgPath, err)
+			}
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// outputFileName returns the path of the output file for the source file srcFile.
+func outputFileName(srcFile string, pattern string) string {
+	base := strings.TrimSuffix(filepath.Base(srcFile), filepath.Ext(srcFile))
+	name := strings.ReplaceAll(pattern, basePlaceholder, base)
+	return filepath.Join(filepath.Dir(srcFile), filepath.FromSlash(name))
+}
+
+func (l *loader) render(files []*parsedFile, absOutput string) (*Result, error) {
+	tdata, err := l.generate(files, absOutput)
+	if err != nil {
+		return nil, fmt.Errorf("generate types failed: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = generateOutput(*tdata, l.opts.TemplateFiles, l.opts.Template, &buf); err != nil {
+		return nil, fmt.Errorf("write output failed: %w", err)
+	}
+	goSrc := buf.Bytes()
+	fmtSrc, err := format.Source(goSrc)
+	if err != nil && l.opts.Check {
+		return nil, fmt.Errorf("format source failed: %w\n%s", err, annotateSource(goSrc, err))
+	}
+	if err != nil {
+		debugFile, debugErr := writeDebugSource(absOutput, goSrc, err)
+		if debugErr != nil {
+			return nil, fmt.Errorf("format source failed: %w", errors.Join(err, debugErr))
+		}
+		return nil, fmt.Errorf("format source failed (unformatted source written to %s): %w", debugFile, err)
+	}
+	return &Result{
+		OutputFile: absOutput,
+		Content:    fmtSrc,
+	}, nil
+}
+
+// reserveTypeNames reserves the names of the wrapper types of files, all the files of a package to generate.
+func (l *loader) reserveTypeNames(files []*parsedFile) {
+	l.typeNames = make(map[string]struct{})
+	for _, file := range files {
+		for _, iface := range file.Interfaces {
+			l.typeNames[prefix(iface.Name.Name, iface.Config.Prefix, l.opts.TypePrefix)] = struct{}{}
+		}
+	}
+}
+
+// checkMethodInstrumentation reports the metrics and log directives of the method f of the interface name
+// which are set on the interface, with the instrumentation in.
+func (l *loader) checkMethodInstrumentation(f wrappedFunction, in instrumentation, name string) {
+	if fmc := f.Config.Metrics; fmc != nil {
+		if in.Metrics == nil {
+			l.recordError(fmc.Pos, fmt.Errorf("metrics: interface %s does not record metrics", name))
+		} else if fmc.NoTrace {
+			l.recordError(fmc.Pos, fmt.Errorf("metrics: notrace is set on the interface, not its methods"))
+		}
+	}
+	if flc := f.Config.Log; flc != nil {
+		l.recordError(flc.Pos, fmt.Errorf("log: set on the interface, not its methods"))
+	}
+}
+
+func (l *loader) generate(files []*parsedFile, outFile string) (*TemplateData, error) {
+	destDir := filepath.Dir(outFile)
+	destPaths, err := getFullPackagePath(destDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not get full package path for %s: %w", outFile, err)
+	}
+	pkgName := filepath.Base(destDir)
+	if files[0].PkgPath == destPaths.packagePath {
+		pkgName = files[0].Package
+	}
+	if l.opts.Package != "" {
+		pkgName = l.opts.Package
+	}
+	exportFile := TemplateData{
+		Version:   TemplateDataVersion,
+		Package:   pkgName,
+		Header:    commentLines(l.opts.Header),
+		BuildTags: l.opts.BuildTags,
+	}
+	it := newTypeImporter(destPaths.packagePath)
+	runtime, err := l.importPackage(runtimePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", runtimePkgPath, err)
+	}
+	it.usePackage(runtime.Types)
+	cache := newAutoSetterFuncCache(it, runtime.Types)
+	for _, file := range files {
+		for _, fn := range file.Functions {
+			l.resolveSetters(fn.Config.Setters)
+			fun, err := l.createWrapperFunction(file, fn, l.instrumentation(fn.Config.Metrics, fn.Config.Log), it, cache)
+			if err != nil {
+				return nil, err
+			}
+			exportFile.Functions = append(exportFile.Functions, fun)
+		}
+	}
+	for _, file := range files {
+		for _, iface := range file.Interfaces {
+			var wi TemplateTypeConfig
+			wi.Name = iface.Name.Name
+			wi.QualifiedName = it.objectString(iface.Object)
+			if iface.Config.ExternalType != nil {
+				obj, err := l.lookupObject(file.Scope, iface.Config.ExternalType)
+				if err != nil {
+					l.recordError(iface.Name.Pos(), fmt.Errorf("external: %w", err))
+					continue
+				}
+				if _, ok := obj.(*types.TypeName); !ok {
+					l.recordError(iface.Name.Pos(), fmt.Errorf("external: %s is not a type", obj.Name()))
+					continue
+				}
+				wi.ExternalType = it.objectString(obj)
+				wi.QualifiedName = wi.ExternalType
+			}
+			wi.ConstructorName = prefix(wi.Name, iface.Config.ConstructorPrefix, l.opts.ConstructorPrefix)
+			wi.TypeName = prefix(wi.Name, iface.Config.Prefix, l.opts.TypePrefix)
+			l.resolveSetters(iface.Config.Setters)
+			in := l.instrumentation(iface.Config.Metrics, iface.Config.Log)
+			in.Recover = iface.Config.Recover
+			wi.NoTrace = in.noTrace()
+			wi.Metrics = in.Metrics != nil
+			if in.Log != nil {
+				wi.Log = true
+				wi.LoggerType = "*" + it.namePackage("log/slog", "slog") + ".Logger"
+			}
+			for _, f := range iface.Functions {
+				l.checkMethodInstrumentation(f, in, wi.Name)
+				fun, err := l.createWrapperFunction(file, f, in, it, cache)
+				if err != nil {
+					return nil, err
+				}
+				wi.Functions = append(wi.Functions, fun)
+			}
+			if wi.Optional, err = l.optionalTypes(file, iface, wi, in, it, cache); err != nil {
+				return nil, err
+			}
+			specs := it.TypeParams(iface.TypeParams)
+			wi.TypeParamSpec = typeParamsToSpec(iface.TypeParams, specs)
+			wi.TypeParamNames = typeParamNames(iface.TypeParams)
+			exportFile.Types = append(exportFile.Types, wi)
+		}
+	}
+	exportFile.Imports = it.Imports()
+	if err = l.diags.err(); err != nil {
+		return nil, fmt.Errorf("could not generate file '%s': %w", outFile, err)
+	}
+	return &exportFile, nil
+}
+
+// commentLines splits text into lines for a // comment block.
+func commentLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func prefix(name string, prefix string, def string) string {
+	if prefix == "" {
+		return fmt.Sprintf("%s%s", def, name)
+	}
+	return fmt.Sprintf("%s%s", prefix, name)
+
+}
+
+func typeParamNames(params []typeParam) string {
+	if len(params) == 0 {
+		return ""
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return "[" + strings.Join(names, ",") + "]"
+}
+
+func typeParamsToSpec(params []typeParam, specs []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	if len(params) != len(specs) {
+		return ""
+	}
+	typeSpecs := make([]string, len(params))
+	for i, p := range params {
+		typeSpecs[i] = fmt.Sprintf("%s %s", p.Name, specs[i])
+	}
+	return "[" + strings.Join(typeSpecs, ",") + "]"
+}
+
+type argNameDisambiguator struct {
+	names map[string]int
+}
+
+func newArgNameDisambiguator(reserved ...string) *argNameDisambiguator {
+	names := make(map[string]int)
+	for _, r := range reserved {
+		names[r] = 0
+	}
+	return &argNameDisambiguator{
+		names: names,
+	}
+}
+
+func (d *argNameDisambiguator) disambiguate(name string) string {
+	if n, ok := d.names[name]; ok {
+		d.names[name] = n + 1
+		return fmt.Sprintf("%s%d", name, n)
+	}
+	d.names[name] = 1
+	return name
+}
+
+// instrumentation is the instrumentation of a wrapper besides tracing.
+type instrumentation struct {
+	Metrics *metricsConfig
+	Log     *logConfig
+	// Recover is set by the recover directive of an interface, for the methods with an error result.
+	Recover bool
+}
+
+func (in instrumentation) noTrace() bool {
+	return (in.Metrics != nil && in.Metrics.NoTrace) || (in.Log != nil && in.Log.NoTrace)
+}
+
+// instrumentation returns the instrumentation in effect for a declaration with the directives mc and lc.
+func (l *loader) instrumentation(mc *metricsConfig, lc *logConfig) instrumentation {
+	if mc == nil && l.opts.Metrics {
+		mc = &metricsConfig{}
+	}
+	if lc == nil && l.opts.Log {
+		lc = &logConfig{}
+	}
+	return instrumentation{Metrics: mc, Log: lc}
+}
+
+// createWrapperFunction creates the template data of f with the instrumentation in.
+func (l *loader) createWrapperFunction(file *parsedFile, f wrappedFunction, in instrumentation, it *typeImporter, cache *autoSetterFuncCache) (fun TemplateFunctionConfig, err error) {
+	fun.Name = f.Name.Name
+	reserved := []string{"span", "tr"}
+	if f.Object.Type().(*types.Signature).Recv() == nil { // package-level function
+		fun.QualifiedName = it.objectString(f.Object)
+	} else {
+		reserved = append(reserved, "w") // the receiver of the wrapper method
+	}
+	if et := f.Config.ExternalType; et != nil {
+		obj, err := l.lookupObject(file.Scope, et)
+		if err != nil {
+			return TemplateFunctionConfig{}, fmt.Errorf("external: %w", err)
+		}
+		if _, ok := obj.(*types.Func); !ok {
+			return TemplateFunctionConfig{}, fmt.Errorf("external: %s is not a function", obj.Name())
+		}
+		fun.QualifiedName = it.objectString(obj)
+	}
+	fun.WrapperName = prefix(fun.Name, f.Config.Prefix, l.opts.FunctionPrefix)
+	ctxArg := -1
+	errArg := -1
+	fun.OperationName = f.Config.OperationName
+	typeSpecs := it.TypeParams(f.TypeParams)
+	fun.TypeParamSpec = typeParamsToSpec(f.TypeParams, typeSpecs)
+	fun.TypeParamNames = typeParamNames(f.TypeParams)
+	fun.TracerArg = "tr"
+	fun.NoTrace = in.noTrace()
+	if in.Metrics != nil {
+		fun.Metrics = true
+		fun.MeterArg = "mt"
+		fun.MeasurementVar = "measurement"
+		reserved = append(reserved, fun.MeterArg, fun.MeasurementVar)
+	}
+	if in.Log != nil {
+		fun.Log = true
+		fun.LoggerArg = "lg"
+		fun.LogCallVar = "logCall"
+		fun.LoggerType = "*" + it.namePackage("log/slog", "slog") + ".Logger"
+		fun.NewLogCall = it.namePackage(slogtracerPkgPath, "slogtracer") + ".NewCall"
+		reserved = append(reserved, fun.LoggerArg, fun.LogCallVar)
+	}
+	d := newArgNameDisambiguator(reserved...)
+	for i, a := range f.Arguments {
+		var arg TemplateFunctionArg
+		arg.Name = a.Name
+		arg.Type = it.resolveArg(a)
+		arg.Variadic = a.Variadic
+		fun.ArgHasAttributes = l.extractFuncArgument(file, &f, &arg, a, attrAlways, it, cache) || fun.ArgHasAttributes
+		if l.typeIsContext(a.Type) && ctxArg == -1 {
+			arg.Name = "ctx"
+			ctxArg = i
+		}
+		if arg.Name == "" {
+			arg.Name = fmt.Sprintf("arg%d", i)
+		}
+		arg.Name = d.disambiguate(arg.Name)
+		for j := range arg.Fields {
+			arg.Fields[j].resolve(arg.Name)
+		}
+		if i == ctxArg {
+			fun.ContextArg = arg.Name
+		}
+		fun.Arguments = append(fun.Arguments, arg)
+	}
+	if ctxArg == -1 {
+		fun.ContextArg = d.disambiguate("ctx")
+		fun.ContextVar = fmt.Sprintf("%s := %s.Background()", fun.ContextArg, it.namePackage("context", "context"))
+	}
+	for i, a := range f.Returns {
+		var arg TemplateFunctionArg
+		arg.Name = a.Name
+		arg.Type = it.resolveArg(a)
+		if l.typeIsError(a.Type) && errArg == -1 {
+			arg.Name = "err"
+			errArg = i
+		}
+		if errArg != i {
+			l.extractFuncArgument(file, &f, &arg, a, attrOnSuccess, it, cache)
+		} else if l.extractFuncArgument(file, &f, &arg, a, attrOnError, it, cache) {
+			// EndError and End already record the error with AttributeSetter.Error,
+			// so it is only set as an attribute by an explicit setter.
+			if setter := f.Config.AttributeFunctions[a.Name]; setter == nil || setter.Func == nil {
+				arg.AttrKey, arg.AttrFunc = "", ""
+				if setter != nil && !l.errorAttrs[setter] {
+					l.errorAttrs[setter] = true
+					l.recordError(setter.Pos, fmt.Errorf("attr: error results are recorded by EndError; name a setter to also set %s as an attribute", a.Name))
+				}
+			}
+		}
+		fun.ReturnHasAttributes = fun.ReturnHasAttributes || arg.AttrKey != "" || len(arg.Fields) > 0
+		if arg.Name == "" {
+			arg.Name = fmt.Sprintf("ret%d", i)
+		}
+		arg.Name = d.disambiguate(arg.Name)
+		for j := range arg.Fields {
+			arg.Fields[j].resolve(arg.Name)
+		}
+		if errArg == i {
+			fun.ErrorReturn = arg.Name
+		}
+		fun.Returns = append(fun.Returns, arg)
+	}
+	if ec := f.Config.ErrClass; ec != nil {
+		if fun.ErrorReturn == "" {
+			l.recordError(ec.Pos, fmt.Errorf("errclass: %s has no error result", fun.Name))
+		} else if classifier, ok := l.errorClassifier(file, ec, it); ok {
+			fun.ErrorClassifier = classifier
+			fun.ErrorClassVar = d.disambiguate("errClass")
+		}
+	}
+	for _, c := range f.Config.Consts {
+		fun.ConstAttributes = append(fun.ConstAttributes, TemplateConstAttr{AttrKey: c.Key, Method: c.Method, Value: c.Value})
+	}
+	for _, ca := range f.Config.ContextAttrs {
+		if attr, ok := l.contextAttr(file, &f, ca, it, cache); ok {
+			fun.ContextAttributes = append(fun.ContextAttributes, attr)
+		}
+	}
+	switch {
+	case f.Config.Recover && fun.ErrorReturn == "":
+		l.recordError(f.Name.Pos(), fmt.Errorf("recover: %s has no error result", fun.Name))
+	case f.Config.Recover, in.Recover:
+		fun.Recover = fun.ErrorReturn != ""
+	}
+	if f.Config.Metrics != nil {
+		for _, key := range f.Config.Metrics.Keys {
+			if !selectMetricAttr(fun.Arguments, key) && !selectMetricAttr(fun.Returns, key) && !fun.selectMetricAttr(key) {
+				l.recordError(f.Config.Metrics.Pos, fmt.Errorf("metrics: no attribute with key %q", key))
+			}
+		}
+	}
+	return fun, nil
+}
+
+// contextAttr creates the template data of the ctxattr directive ca of f.
+// It reports errors at the directive, and returns false if it is invalid.
+func (l *loader) contextAttr(file *parsedFile, f *wrappedFunction, ca *ctxAttr, it *typeImporter, cache *autoSetterFuncCache) (TemplateContextAttr, bool) {
+	scope := file.Scope
+	if ca.Scope != nil {
+		scope = ca.Scope
+	}
+	obj, err := l.lookupObject(scope, ca.Func)
+	if err != nil {
+		l.recordError(ca.Pos, fmt.Errorf("ctxattr: %w", err))
+		return TemplateContextAttr{}, false
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		l.recordError(ca.Pos, fmt.Errorf("ctxattr: %s is not a function", obj.Name()))
+		return TemplateContextAttr{}, false
+	}
+	sig := fn.Type().(*types.Signature)
+	params, results := sig.Params(), sig.Results()
+	hasOK := results.Len() == 2 && types.Identical(results.At(1).Type(), types.Typ[types.Bool])
+	if sig.TypeParams().Len() > 0 || params.Len() != 1 || !l.typeIsContext(params.At(0).Type()) || (results.Len() != 1 && !hasOK) {
+		l.recordError(ca.Pos, fmt.Errorf("ctxattr: %s is not a func(context.Context) T or func(context.Context) (T, bool)", fn.Name()))
+		return TemplateContextAttr{}, false
+	}
+	t := results.At(0).Type()
+	setter := &attributeKeyFunc{Key: ca.Key, Func: ca.Setter, Pos: ca.Pos, Scope: ca.Scope}
+	attrFunc, ok := l.attrSetterFunc(file, f, setter, fn.Name(), t, it.describeType(t), ca.Pos, it, cache)
+	if !ok {
+		return TemplateContextAttr{}, false
+	}
+	return TemplateContextAttr{
+		AttrKey:  ca.Key,
+		Func:     it.objectString(fn),
+		HasOK:    hasOK,
+		AttrFunc: attrFunc,
+	}, true
+}
+
+// errorClassifier returns the function of the errclass directive ec, a function or variable of type
+// func(error) genstrument.ErrorClass. It reports errors at the directive, and returns false if it is invalid.
+func (l *loader) errorClassifier(file *parsedFile, ec *errClass, it *typeImporter) (string, bool) {
+	scope := file.Scope
+	if ec.Scope != nil {
+		scope = ec.Scope
+	}
+	obj, err := l.lookupObject(scope, ec.Func)
+	if err != nil {
+		l.recordError(ec.Pos, fmt.Errorf("errclass: %w", err))
+		return "", false
+	}
+	switch obj.(type) {
+	case *types.Func, *types.Var:
+	default:
+		l.recordError(ec.Pos, fmt.Errorf("errclass: %s is not a function", obj.Name()))
+		return "", false
+	}
+	errorClass, err := l.runtimeType("ErrorClass")
+	if err != nil {
+		l.recordError(ec.Pos, fmt.Errorf("errclass: %w", err))
+		return "", false
+	}
+	sig, ok := obj.Type().Underlying().(*types.Signature)
+	if !ok || sig.TypeParams().Len() > 0 || sig.Params().Len() != 1 || !l.typeIsError(sig.Params().At(0).Type()) ||
+		sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), errorClass) {
+		l.recordError(ec.Pos, fmt.Errorf("errclass: %s is not a func(error) genstrument.ErrorClass", obj.Name()))
+		return "", false
+	}
+	return it.objectString(obj), true
+}
+
+// selectMetricAttr marks the const and ctxattr attributes with the attribute key as metric attributes.
+func (fun *TemplateFunctionConfig) selectMetricAttr(key string) (found bool) {
+	for i := range fun.ConstAttributes {
+		if fun.ConstAttributes[i].AttrKey == key {
+			fun.ConstAttributes[i].MetricAttr = true
+			found = true
+		}
+	}
+	for i := range fun.ContextAttributes {
+		if fun.ContextAttributes[i].AttrKey == key {
+			fun.ContextAttributes[i].MetricAttr = true
+			found = true
+		}
+	}
+	return found
+}
+
+// selectMetricAttr marks the arguments with the attribute key as metric attributes.
+func selectMetricAttr(args []TemplateFunctionArg, key string) (found bool) {
+	for i := range args {
+		if args[i].AttrKey == key && args[i].AttrFunc != "" {
+			args[i].MetricAttr = true
+			found = true
+		}
+		for j := range args[i].Fields {
+			if args[i].Fields[j].AttrKey == key {
+				args[i].Fields[j].MetricAttr = true
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// extractFuncArgument sets the attributes of the argument or result a on arg, with the mode defaultMode unless their
+// directive has one, and reports whether it has any.
+func (l *loader) extractFuncArgument(file *parsedFile, f *wrappedFunction, arg *TemplateFunctionArg, a funcArg, defaultMode string, it *typeImporter, cache *autoSetterFuncCache) bool {
+	found := false
+	mode := func(setter *attributeKeyFunc) string {
+		if setter.Mode == attrOnError && !l.hasErrorResult(f) {
+			l.recordError(setter.Pos, fmt.Errorf("attr: %s has no error result to set %s on", f.Name.Name, setter.Key))
+		}
+		if setter.Mode != "" {
+			return setter.Mode
+		}
+		return defaultMode
+	}
+	if setter, ok := f.Config.AttributeFunctions[a.Name]; ok && setter.Key != "" {
+		arg.AttrKey = setter.Key
+		arg.AttrMode = mode(setter)
+		arg.AttrFunc, ok = l.attrSetterFunc(file, f, setter, a.Name, a.Type, arg.Type, a.Pos, it, cache)
+		found = ok
+	}
+	for _, setter := range fieldAttrs(f.Config.AttributeFunctions, a.Name) {
+		fa, t, err := l.resolveAttrPath(f, a.Type, setter.Path, it)
+		if err != nil {
+			l.recordError(setter.Pos, fmt.Errorf("attr: %w", err))
+			continue
+		}
+		fn, ok := l.attrSetterFunc(file, f, setter, a.Name, t, it.describeType(t), setter.Pos, it, cache)
+		if !ok {
+			continue
+		}
+		fa.AttrKey = setter.Key
+		fa.AttrMode = mode(setter)
+		fa.AttrFunc = fn
+		arg.Fields = append(arg.Fields, fa)
+		found = true
+	}
+	return found
+}
+
+// hasErrorResult reports whether f returns an error.
+func (l *loader) hasErrorResult(f *wrappedFunction) bool {
+	for _, r := range f.Returns {
+		if l.typeIsError(r.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// attrSetterFunc returns the setter function of the attribute set from a value of type t, named typeName in the output.
+// It reports errors at pos, and returns false if there is none.
+func (l *loader) attrSetterFunc(file *parsedFile, f *wrappedFunction, setter *attributeKeyFunc, name string, t types.Type, typeName string, pos token.Pos, it *typeImporter, cache *autoSetterFuncCache) (string, bool) {
+	if setter.Func != nil {
+		scope := file.Scope
+		if setter.Scope != nil {
+			scope = setter.Scope
+		}
+		obj, err := l.lookupObject(scope, setter.Func)
+		if err != nil {
+			l.recordError(pos, fmt.Errorf("could not resolve attribute function: %w", err))
+			return "", false
+		}
+		if _, ok := obj.(*types.Func); !ok {
+			l.recordError(pos, fmt.Errorf("attribute setter %s is not a function", obj.Name()))
+			return "", false
+		}
+		return it.objectString(obj), true
+	}
+	if tp, ok := t.(*types.TypeParam); ok {
+		l.recordError(pos, fmt.Errorf("cannot find auto-setter function for generic type %s", tp))
+		return "", false
+	}
+	if t == nil {
+		l.recordError(pos, fmt.Errorf("cannot find type of argument %s", name))
+		return "", false
+	}
+	if fn := l.registeredSetter(f.Config.Setters, t); fn != nil {
+		return it.objectString(fn), true
+	}
+	fn, err := cache.autoSetterFunc(t)
+	if err != nil {
+		l.recordError(pos, fmt.Errorf("%w for type %s", err, typeName))
+		return "", false
+	}
+	return fn, true
+}
+
+const (
+	runtimePkgPath    = "github.com/justenwalker/genstrument"
+	slogtracerPkgPath = runtimePkgPath + "/slogtracer"
+)
@@ -0,0 +1,671 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputFile  string
+		outputFile string
+	}{
+		{
+			name:       "simple",
+			inputFile:  "../../example/simple.go",
+			outputFile: "../../example/gen/simple.gen.go",
+		},
+		{
+			name:       "complex",
+			inputFile:  "../../example/complex.go",
+			outputFile: "../../example/gen/complex.gen.go",
+		},
+		{
+			name:       "embedded",
+			inputFile:  "../../example/embedded.go",
+			outputFile: "../../example/gen/embedded.gen.go",
+		},
+		{
+			name:       "exprs",
+			inputFile:  "../../example/exprs.go",
+			outputFile: "../../example/gen/exprs.gen.go",
+		},
+		{
+			name:       "collide",
+			inputFile:  "../../example/collide.go",
+			outputFile: "../../example/gen/collide.gen.go",
+		},
+		{
+			name:       "metrics",
+			inputFile:  "../../example/metrics.go",
+			outputFile: "../../example/gen/metrics.gen.go",
+		},
+		{
+			name:       "logging",
+			inputFile:  "../../example/logging.go",
+			outputFile: "../../example/gen/logging.gen.go",
+		},
+		{
+			name:       "panics",
+			inputFile:  "../../example/panics.go",
+			outputFile: "../../example/gen/panics.gen.go",
+		},
+		{
+			name:       "optional",
+			inputFile:  "../../example/optional.go",
+			outputFile: "../../example/gen/optional.gen.go",
+		},
+		{
+			name:       "opformat",
+			inputFile:  "../../example/opformat.go",
+			outputFile: "../../example/gen/opformat.gen.go",
+		},
+		{
+			name:       "marshaler",
+			inputFile:  "../../example/marshaler.go",
+			outputFile: "../../example/gen/marshaler.gen.go",
+		},
+		{
+			name:       "autosetters",
+			inputFile:  "../../example/autosetters.go",
+			outputFile: "../../example/gen/autosetters.gen.go",
+		},
+		{
+			name:       "fields",
+			inputFile:  "../../example/fields.go",
+			outputFile: "../../example/gen/fields.gen.go",
+		},
+		{
+			name:       "staticattrs",
+			inputFile:  "../../example/staticattrs.go",
+			outputFile: "../../example/gen/staticattrs.gen.go",
+		},
+		{
+			name:       "modes",
+			inputFile:  "../../example/modes.go",
+			outputFile: "../../example/gen/modes.gen.go",
+		},
+		{
+			name:       "errclass",
+			inputFile:  "../../example/errclass.go",
+			outputFile: "../../example/gen/errclass.gen.go",
+		},
+		{
+			name:       "external",
+			inputFile:  "../../example/external/external.go",
+			outputFile: "../../example/external/external.gen.go",
+		},
+	}
+	g := goldie.New(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			r, err := Generate(context.Background(), Config{
+				InputFile:  tt.inputFile,
+				OutputFile: tt.outputFile,
+			})
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			g.Assert(t, tt.name, r.Content)
+		})
+	}
+}
+
+func TestGenerateCrossFile(t *testing.T) {
+	r, err := Generate(context.Background(), Config{
+		InputFile:  "testdata/crossfile/service.go",
+		OutputFile: "testdata/crossfile/genstrument.gen.go",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	goldie.New(t).Assert(t, "crossfile", r.Content)
+}
+
+func TestGeneratePackages(t *testing.T) {
+	tests := []struct {
+		name          string
+		patterns      []string
+		outputPattern string
+		outputs       []string
+	}{
+		{
+			name:     "pkgmode",
+			patterns: []string{"../../example/pkgmode"},
+			outputs:  []string{"../../example/pkgmode/genstrument.gen.go"},
+		},
+		{
+			name:          "pkgmode_per_file",
+			patterns:      []string{"../../example/pkgmode"},
+			outputPattern: "{{base}}_genstrument.go",
+			outputs: []string{
+				"../../example/pkgmode/flusher_genstrument.go",
+				"../../example/pkgmode/ping_genstrument.go",
+				"../../example/pkgmode/store_genstrument.go",
+			},
+		},
+		{
+			// The output of a previous run, missing a method added to Store, does not type-check.
+			name:     "stale",
+			patterns: []string{"./testdata/stale"},
+			outputs:  []string{"testdata/stale/genstrument.gen.go"},
+		},
+	}
+	g := goldie.New(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := GeneratePackages(context.Background(), Config{
+				Packages: tt.patterns,
+				Options: Options{
+					OutputPattern: tt.outputPattern,
+				},
+			})
+			if err != nil {
+				t.Fatalf("GeneratePackages failed: %v", err)
+			}
+			if len(results) != len(tt.outputs) {
+				t.Fatalf("expected %d results, got %d", len(tt.outputs), len(results))
+			}
+			for i, r := range results {
+				want, err := filepath.Abs(tt.outputs[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if r.OutputFile != want {
+					t.Errorf("result %d: expected output file %s, got %s", i, want, r.OutputFile)
+				}
+				name := tt.name
+				if len(results) > 1 {
+					name = tt.name + "_" + strings.TrimSuffix(filepath.Base(want), ".go")
+				}
+				g.Assert(t, name, r.Content)
+			}
+		})
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	g := goldie.New(t)
+	r, err := Generate(context.Background(), Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+		Options: Options{
+			Package:           "traced",
+			ConstructorPrefix: "Trace",
+			TypePrefix:        "traced",
+			FunctionPrefix:    "Observe",
+			Header:            "Copyright Example Authors\n\nSPDX-License-Identifier: MIT",
+			BuildTags:         "!notrace",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	g.Assert(t, "options", r.Content)
+}
+
+func TestGenerateOperationFormat(t *testing.T) {
+	r, err := Generate(context.Background(), Config{
+		InputFile:  "../../example/metrics.go",
+		OutputFile: "../../example/gen/metrics.gen.go",
+		Options: Options{
+			OperationFormat: "{{.PkgPath}}/{{.Interface}}.{{.Method}}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if want := `w.cfg.OperationName("genstrument/example/MetricsService.Lookup")`; !bytes.Contains(r.Content, []byte(want)) {
+		t.Errorf("expected the operation name %s, got:\n%s", want, r.Content)
+	}
+	// Quotes and backslashes in operation names are escaped.
+	r, err = Generate(context.Background(), Config{
+		InputFile:  "../../example/metrics.go",
+		OutputFile: "../../example/gen/metrics.gen.go",
+		Options: Options{
+			OperationFormat: `{{with .Interface}}{{.}}\{{end}}"{{.Method}}"`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, want := range []string{`w.cfg.OperationName("MetricsService\\\"Lookup\"")`, `"\"MeteredFunction\""`} {
+		if !bytes.Contains(r.Content, []byte(want)) {
+			t.Errorf("expected the operation name %s, got:\n%s", want, r.Content)
+		}
+	}
+	_, err = Generate(context.Background(), Config{
+		InputFile:  "../../example/metrics.go",
+		OutputFile: "../../example/gen/metrics.gen.go",
+		Options: Options{
+			OperationFormat: "{{.Method",
+		},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "operation format: ") {
+		t.Errorf("expected an operation format error, got %v", err)
+	}
+}
+
+func TestGenerateDiagnostics(t *testing.T) {
+	type diagnostic struct {
+		line int
+		msg  string
+	}
+	tests := []struct {
+		file string
+		want []diagnostic
+	}{
+		{
+			file: "invalid.go",
+			want: []diagnostic{
+				{line: 8, msg: "unknown interface comment: bogus"},
+				{line: 10, msg: "attr: expected 2 or 3 arguments, got 1"},
+			},
+		},
+		{
+			file: "metrics.go",
+			want: []diagnostic{
+				{line: 9, msg: "metrics: interface Unmetered does not record metrics"},
+				{line: 18, msg: `metrics: no attribute with key "missing"`},
+			},
+		},
+		{
+			file: "log.go",
+			want: []diagnostic{
+				{line: 10, msg: "log: set on the interface, not its methods"},
+			},
+		},
+		{
+			file: "recover.go",
+			want: []diagnostic{
+				{line: 10, msg: "recover: Get has no error result"},
+			},
+		},
+		{
+			file: "optional.go",
+			want: []diagnostic{
+				{line: 11, msg: "optional: Closer has no methods besides those of Conflicting"},
+				{line: 12, msg: "optional: method Read of ReadCloser is also in Conflicting"},
+				{line: 12, msg: "optional: ReadCloser has no methods besides those of Conflicting"},
+				{line: 12, msg: "optional: package fmt is not imported"},
+				{line: 13, msg: "optional: Background is not an interface"},
+				{line: 13, msg: "optional: undefined: Missing"},
+			},
+		},
+		{
+			file: "optionalmethod.go",
+			want: []diagnostic{
+				{line: 7, msg: "log: set on the interface, not its methods"},
+			},
+		},
+		{
+			file: "defaults.go",
+			want: []diagnostic{
+				{line: 2, msg: "metrics: attribute keys are selected on methods, not defaults"},
+				{line: 3, msg: "unknown defaults comment: wrap"},
+			},
+		},
+		{
+			file: "nodefaults.go",
+			want: []diagnostic{
+				{line: 1, msg: "package clause directives must follow a defaults directive"},
+			},
+		},
+		{
+			file: "autosetter.go",
+			want: []diagnostic{
+				{line: 18, msg: "ambiguous auto-setter functions genstrument.SetErrorAttribute, genstrument.SetStringerAttribute for type Status"},
+				{line: 20, msg: "cannot find auto-setter function for type []fmt.Stringer"},
+			},
+		},
+		{
+			file: "fields.go",
+			want: []diagnostic{
+				{line: 22, msg: "attr: *Item has no field or method Missing"},
+				{line: 23, msg: "attr: Get is a method, call it with Get()"},
+				{line: 24, msg: "attr: Name is a field, not a method"},
+				{line: 25, msg: "attr: cannot call pointer method Title on Item"},
+			},
+		},
+		{
+			file: "fieldsyntax.go",
+			want: []diagnostic{
+				{line: 9, msg: "attr: invalid argument \"item.Header.Get(\\\"X\\\")\": only methods without arguments can be called"},
+				{line: 10, msg: "attr: invalid argument \"item.Values[0]\": expected an argument name or a field path like req.User.ID"},
+			},
+		},
+		{
+			file: "ctxattr.go",
+			want: []diagnostic{
+				{line: 16, msg: "ctxattr: undefined: Missing"},
+				{line: 17, msg: "ctxattr: NoContext is not a func(context.Context) T or func(context.Context) (T, bool)"},
+				{line: 18, msg: "ctxattr: Maybe is not a func(context.Context) T or func(context.Context) (T, bool)"},
+				{line: 19, msg: "cannot find auto-setter function for type Tenant"},
+			},
+		},
+		{
+			file: "ctxattrsyntax.go",
+			want: []diagnostic{
+				{line: 8, msg: "const: expected a key and a value"},
+				{line: 9, msg: "ctxattr: expected 2 or 3 arguments, got 1"},
+			},
+		},
+		{
+			file: "attrmode.go",
+			want: []diagnostic{
+				{line: 10, msg: "attr: Count has no error result to set failed.key on"},
+			},
+		},
+		{
+			file: "errorattr.go",
+			want: []diagnostic{
+				{line: 8, msg: "attr: error results are recorded by EndError; name a setter to also set err as an attribute"},
+				{line: 12, msg: "attr: error results are recorded by EndError; name a setter to also set err as an attribute"},
+			},
+		},
+		{
+			file: "attrmodesyntax.go",
+			want: []diagnostic{
+				{line: 8, msg: "attr: expected 2 or 3 arguments, got 4"},
+			},
+		},
+		{
+			file: "errclass.go",
+			want: []diagnostic{
+				{line: 13, msg: "errclass: undefined: Missing"},
+				{line: 15, msg: "errclass: ClassifyString is not a func(error) genstrument.ErrorClass"},
+				{line: 17, msg: "errclass: NotAFunc is not a func(error) genstrument.ErrorClass"},
+				{line: 19, msg: "errclass: ErrClasses is not a function"},
+				{line: 21, msg: "errclass: NoError has no error result"},
+			},
+		},
+		{
+			file: "errclasssyntax.go",
+			want: []diagnostic{
+				{line: 8, msg: "errclass: expected 1 argument, got 2"},
+			},
+		},
+		{
+			file: "setter.go",
+			want: []diagnostic{
+				{line: 23, msg: "setter: NoSetter is not a func(Point, genstrument.AttributeSetter)"},
+				{line: 24, msg: "setter: Point does not satisfy the type constraint of SetStringish"},
+				{line: 25, msg: "setter: ID cannot be passed to SetPoint"},
+				{line: 26, msg: "setter: undefined: Missing"},
+				{line: 27, msg: "setter: SetPoint is not a type"},
+			},
+		},
+		{
+			file: "opformat.go",
+			want: []diagnostic{
+				{line: 8, msg: "opformat: template: opformat:1: unclosed action"},
+				{line: 18, msg: `opformat: template: opformat:1:2: executing "opformat" at <.Receiver>: can't evaluate field Receiver in type gen.OperationNameData`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			_, err := Generate(context.Background(), Config{
+				InputFile:  filepath.Join("testdata/invalid", tt.file),
+				OutputFile: "testdata/invalid/invalid.gen.go",
+			})
+			var diags Diagnostics
+			if !errors.As(err, &diags) {
+				t.Fatalf("expected Diagnostics, got %v", err)
+			}
+			if len(diags) != len(tt.want) {
+				t.Fatalf("expected %d diagnostics, got %d: %v", len(tt.want), len(diags), diags)
+			}
+			for i, w := range tt.want {
+				d := diags[i]
+				if filepath.Base(d.Pos.Filename) != tt.file || d.Pos.Line != w.line || d.Err.Error() != w.msg {
+					t.Errorf("diagnostic %d: expected %s:%d: %s, got %v", i, tt.file, w.line, w.msg, d)
+				}
+			}
+		})
+	}
+}
+
+func TestResultDiff(t *testing.T) {
+	r, err := Generate(context.Background(), Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	diff, err := r.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff != "" {
+		t.Fatalf("expected checked-in output to be up-to-date, got diff:\n%s", diff)
+	}
+	stale := &Result{
+		OutputFile: r.OutputFile,
+		Content:    bytes.Replace(r.Content, []byte(`"helloOp"`), []byte(`"goodbyeOp"`), 1),
+	}
+	diff, err = stale.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, `-	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("helloOp"))`) || !strings.Contains(diff, `+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("goodbyeOp"))`) {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+	missing := &Result{
+		OutputFile: filepath.Join(t.TempDir(), "missing.gen.go"),
+		Content:    r.Content,
+	}
+	diff, err = missing.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.HasPrefix(diff, "--- /dev/null") {
+		t.Errorf("expected diff against /dev/null, got:\n%s", diff)
+	}
+}
+
+func TestResultWriteOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "sub", "out.gen.go")
+	r := &Result{OutputFile: out, Content: []byte("package sub\n")}
+	if err := r.WriteOutput(); err != nil {
+		t.Fatalf("WriteOutput failed: %v", err)
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(out, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteOutput(); err != nil {
+		t.Fatalf("WriteOutput failed: %v", err)
+	}
+	if fi, err := os.Stat(out); err != nil {
+		t.Fatal(err)
+	} else if !fi.ModTime().Equal(old) {
+		t.Errorf("unchanged output was rewritten: mod time %v, want %v", fi.ModTime(), old)
+	}
+	r.Content = []byte("package sub // changed\n")
+	if err := r.WriteOutput(); err != nil {
+		t.Fatalf("WriteOutput failed: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, r.Content) {
+		t.Errorf("got content %q, want %q", got, r.Content)
+	}
+	entries, err := os.ReadDir(filepath.Dir(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the output file, got %d entries", len(entries))
+	}
+}
+
+func TestAnnotateSource(t *testing.T) {
+	src := []byte("package p\n\nfunc f() {\n\treturn 1 +\n}\n")
+	_, err := format.Source(src)
+	if err == nil {
+		t.Fatal("expected format error")
+	}
+	got := string(annotateSource(src, err))
+	want := "package p\n\nfunc f() {\n\treturn 1 +\n}\n^ genstrument: format error: expected operand, found '}'\n"
+	if got != want {
+		t.Errorf("annotateSource:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateCheckFormatError(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	_, err := Generate(context.Background(), Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+		Options: Options{
+			Template: "package {{ .Package }}\n\nfunc f() {\n\treturn 1 +\n}\n",
+			Check:    true,
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "^ genstrument: format error: expected operand") {
+		t.Fatalf("expected the annotated source in the error, got %v", err)
+	}
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no file written in check mode, got %d entries", len(entries))
+	}
+}
+
+func TestGenerateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Generate(ctx, Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Generate: expected context.Canceled, got %v", err)
+	}
+	_, err = GeneratePackages(ctx, Config{Packages: []string{"../../example/pkgmode"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GeneratePackages: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	g := goldie.New(t)
+	r, err := Generate(context.Background(), Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+		Options: Options{
+			TemplateFiles: []string{"testdata/templates/constructor.tmpl"},
+			Template: `{{ define "function" }}
+// {{ .WrapperName }} returns {{ .QualifiedName }} untraced.
+// Arguments: {{ join (arg_names .Arguments) ", " }}
+func {{ .WrapperName }}({{ .TracerArg }} genstrument.Tracer) func({{ . | arg_list }}) {{ . | return_list }} {
+	return {{ .QualifiedName }}
+}
+{{ end }}`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	g.Assert(t, "template", r.Content)
+
+	r, err = Generate(context.Background(), Config{
+		InputFile:  "../../example/simple.go",
+		OutputFile: "../../example/gen/simple.gen.go",
+		Options: Options{
+			Template: "package {{ .Package }} // v{{ .Version }}\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if want := "package gen // v4\n"; string(r.Content) != want {
+		t.Errorf("expected the file template to be replaced, got %q", r.Content)
+	}
+}
+
+func TestRuntimeTypeMissing(t *testing.T) {
+	l, err := newLoader(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a runtime package older than the types used by directives
+	l.pkgPathToPackage[runtimePkgPath] = &packages.Package{
+		PkgPath: runtimePkgPath,
+		Types:   types.NewPackage(runtimePkgPath, "genstrument"),
+	}
+	for _, name := range []string{"AttributeSetter", "ErrorClass"} {
+		_, err = l.runtimeType(name)
+		if want := "github.com/justenwalker/genstrument has no " + name + " type, it is too old for this directive"; err == nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+}
+
+func TestConstValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		method  string
+		literal string
+	}{
+		{value: "true", method: "Bool", literal: "true"},
+		{value: "false", method: "Bool", literal: "false"},
+		{value: "100", method: "Int64", literal: "100"},
+		{value: "0", method: "Int64", literal: "0"},
+		{value: "-42", method: "Int64", literal: "-42"},
+		{value: "+7", method: "Int64", literal: "7"},
+		{value: "1.5", method: "Float64", literal: "1.5"},
+		{value: "0.25", method: "Float64", literal: "0.25"},
+		{value: ".5", method: "Float64", literal: "0.5"},
+		{value: "1e3", method: "Float64", literal: "1000"},
+		// integers out of the range of int64 are kept as written
+		{value: "9223372036854775807", method: "Int64", literal: "9223372036854775807"},
+		{value: "-9223372036854775808", method: "Int64", literal: "-9223372036854775808"},
+		{value: "9223372036854775808", method: "String", literal: `"9223372036854775808"`},
+		{value: "99999999999999999999", method: "String", literal: `"99999999999999999999"`},
+		{value: "1e20", method: "Float64", literal: "1e+20"},
+		// leading zeros, like zip codes, are kept as written
+		{value: "02134", method: "String", literal: `"02134"`},
+		{value: "08", method: "String", literal: `"08"`},
+		{value: "00", method: "String", literal: `"00"`},
+		{value: "01.5", method: "String", literal: `"01.5"`},
+		// only decimal literals are numbers
+		{value: "0x1F", method: "String", literal: `"0x1F"`},
+		{value: "0b101", method: "String", literal: `"0b101"`},
+		{value: "0o17", method: "String", literal: `"0o17"`},
+		{value: "1_000", method: "String", literal: `"1_000"`},
+		{value: "Inf", method: "String", literal: `"Inf"`},
+		{value: "NaN", method: "String", literal: `"NaN"`},
+		{value: "postgres", method: "String", literal: `"postgres"`},
+		{value: `"42"`, method: "String", literal: `"42"`},
+		{value: `"unterminated`, method: "String", literal: `"\"unterminated"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			method, literal := constValue(tt.value)
+			if method != tt.method || literal != tt.literal {
+				t.Errorf("constValue(%q) = %s, %s; want %s, %s", tt.value, method, literal, tt.method, tt.literal)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+type typeImporter struct {
+	currentPackage string
+	importsUsed    map[string]struct{}
+	importsNamed   map[string]int
+	packageToName  map[string]string
+	packageNames   map[string]string
+}
+
+func newTypeImporter(pkgPath string) *typeImporter {
+	return &typeImporter{
+		currentPackage: pkgPath,
+		importsUsed:    make(map[string]struct{}),
+		importsNamed:   make(map[string]int),
+		packageToName:  make(map[string]string),
+		packageNames:   make(map[string]string),
+	}
+}
+
+// usePackage returns the name by which pkg is referred to in the destination package.
+func (it *typeImporter) usePackage(pkg *types.Package) string {
+	return it.namePackage(pkg.Path(), pkg.Name())
+}
+
+// objectString renders a reference to the package-level object obj from the destination package.
+func (it *typeImporter) objectString(obj types.Object) string {
+	if obj.Pkg() == nil { // universe
+		return obj.Name()
+	}
+	if q := it.qualifier(obj.Pkg()); q != "" {
+		return q + "." + obj.Name()
+	}
+	return obj.Name()
+}
+
+// qualifier is a types.Qualifier which names packages relative to the destination package,
+// adding them to the imports of the generated file.
+func (it *typeImporter) qualifier(pkg *types.Package) string {
+	if pkg.Path() == it.currentPackage {
+		return ""
+	}
+	return it.namePackage(pkg.Path(), pkg.Name())
+}
+
+// typeString renders typ as it should appear in the destination package.
+func (it *typeImporter) typeString(typ types.Type) string {
+	return types.TypeString(typ, it.qualifier)
+}
+
+// describeType renders typ for diagnostics like typeString, without adding imports to the generated file.
+func (it *typeImporter) describeType(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg.Path() == it.currentPackage {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// resolveArg renders the type of the argument as it appears in a parameter list.
+func (it *typeImporter) resolveArg(a funcArg) string {
+	if a.Variadic {
+		return "..." + it.typeString(a.Type.(*types.Slice).Elem())
+	}
+	return it.typeString(a.Type)
+}
+
+func (it *typeImporter) namePackage(pkgPath string, pkgName string) string {
+	if name, ok := it.packageToName[pkgPath]; ok { // already named
+		return name
+	}
+	it.importsUsed[pkgPath] = struct{}{}
+	it.packageNames[pkgPath] = pkgName
+	switch pkgName {
+	case "err", "ctx", "span": // don't use common var names as package names
+		pkgName = "_" + pkgName
+	}
+	name := pkgName
+	for {
+		if i, ok := it.importsNamed[name]; ok { // someone has our desired name
+			it.importsNamed[name] = i + 1
+			name = fmt.Sprintf("%s%d", pkgName, i+1) // increment and try again
+			continue
+		}
+		// found a valid name
+		it.importsNamed[name] = 0
+		it.packageToName[pkgPath] = name
+		return name
+	}
+}
+
+func (it *typeImporter) Imports() []TemplateImport {
+	imports := make([]TemplateImport, 0, len(it.packageToName))
+	for pkg, name := range it.packageToName {
+		if it.currentPackage == pkg {
+			continue // do not import current package
+		}
+		imports = append(imports, TemplateImport{
+			Name:    name,
+			Package: it.packageNames[pkg],
+			PkgPath: pkg,
+		})
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		a, b := imports[i], imports[j]
+		return strings.Compare(a.PkgPath, b.PkgPath) < 0
+	})
+	return imports
+}
+
+func (it *typeImporter) TypeParams(tps []typeParam) []string {
+	strs := make([]string, len(tps))
+	for i, tp := range tps {
+		strs[i] = it.typeString(tp.Constraint)
+	}
+	return strs
+}
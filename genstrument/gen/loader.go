@@ -0,0 +1,585 @@
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type loader struct {
+	// ctx cancels loading the packages.
+	ctx  context.Context
+	fset *token.FileSet
+	pkg  *packages.Package
+	// methodFields are the interface method fields of the package being loaded, by the position of their names.
+	methodFields     map[token.Pos]*ast.Field
+	pkgPathToPackage map[string]*packages.Package
+	opts             Options
+	diags            Diagnostics
+	// opFormat is the operation name template of Options.OperationFormat.
+	opFormat *template.Template
+	// pkgDefaults are the defaults of the doc.go file of the package being loaded,
+	// and defaults those of the file being loaded, merged with pkgDefaults.
+	pkgDefaults fileConfig
+	defaults    fileConfig
+	// scope is the scope of the file whose directives are being parsed.
+	scope *types.Scope
+	// setters caches the resolved setter directives.
+	setters map[*setterConfig]resolvedSetter
+	// typeNames are the names of the wrapper types of the package being generated, reserved across its output files
+	// so that the names of optional types do not collide with them or with each other.
+	typeNames map[string]struct{}
+	// errorAttrs are the attr directives of error results without a setter already reported,
+	// once for all the methods inheriting them.
+	errorAttrs map[*attributeKeyFunc]bool
+}
+
+func newLoader(ctx context.Context, opts Options) (*loader, error) {
+	l := &loader{
+		ctx:              ctx,
+		opts:             opts,
+		pkgPathToPackage: make(map[string]*packages.Package),
+		setters:          make(map[*setterConfig]resolvedSetter),
+		errorAttrs:       make(map[*attributeKeyFunc]bool),
+	}
+	if opts.OperationFormat != "" {
+		var err error
+		if l.opFormat, err = parseOpFormat(opts.OperationFormat); err != nil {
+			return nil, fmt.Errorf("operation format: %w", err)
+		}
+	}
+	return l, nil
+}
+
+func (l *loader) loadInputFile(inputFile string) (*parsedFile, error) {
+	absInput, err := filepath.Abs(filepath.Clean(inputFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not get absolute path of %s: %w", inputFile, err)
+	}
+	l.fset = token.NewFileSet()
+	cfg := &packages.Config{
+		Fset: l.fset,
+		Mode: packages.NeedTypesInfo | packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedCompiledGoFiles,
+	}
+	pkgs, err := l.load(cfg, "file="+absInput)
+	if err != nil {
+		return nil, fmt.Errorf("error loading packages: %w", err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package, got %d", len(pkgs))
+	}
+	return l.loadPackage(absInput, pkgs[0])
+}
+
+// load loads the packages matching patterns, stopping when l.ctx is canceled.
+func (l *loader) load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	cfg.Context = l.ctx
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil && l.ctx.Err() != nil {
+		// packages.Load does not wrap the error of the context.
+		return nil, l.ctx.Err()
+	}
+	return pkgs, err
+}
+
+// importPackage returns the package with the given path, loading it if it is not a dependency
+// of the package being generated.
+func (l *loader) importPackage(pkgPath string) (*packages.Package, error) {
+	pkg, ok := l.pkgPathToPackage[pkgPath]
+	if ok {
+		return pkg, nil
+	}
+	cfg := &packages.Config{
+		Fset: l.fset,
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+	}
+	pkgs, err := l.load(cfg, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 || pkg.Types == nil {
+			return nil, fmt.Errorf("could not load package %s: %v", pkgPath, pkg.Errors)
+		}
+		l.pkgPathToPackage[pkg.PkgPath] = pkg
+	}
+	pkg, ok = l.pkgPathToPackage[pkgPath]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found", pkgPath)
+	}
+	return pkg, nil
+}
+
+// runtimeType returns the type of the runtime package with the name, required by a directive.
+// The runtime package imported by the generated code may be too old to have it.
+func (l *loader) runtimeType(name string) (types.Type, error) {
+	runtime, err := l.importPackage(runtimePkgPath)
+	if err != nil {
+		return nil, err
+	}
+	tn, ok := runtime.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s type, it is too old for this directive", runtimePkgPath, name)
+	}
+	return tn.Type(), nil
+}
+
+// loadPackages loads every package matching patterns with a single call to packages.Load.
+func (l *loader) loadPackages(patterns []string) ([]*packages.Package, error) {
+	l.fset = token.NewFileSet()
+	cfg := &packages.Config{
+		Fset: l.fset,
+		Mode: packages.NeedTypesInfo | packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedCompiledGoFiles,
+	}
+	pkgs, err := l.load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matched %s", strings.Join(patterns, " "))
+	}
+	var errs []error
+	for _, pkg := range pkgs {
+		errs = append(errs, packageErrors(pkg)...)
+	}
+	if err = errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("error loading packages: %w", err)
+	}
+	return pkgs, nil
+}
+
+// packageErrors returns the errors of pkg, except type errors in generated files.
+// The output of a previous run no longer type-checks once a wrapped interface changes,
+// and must not stop it from being regenerated.
+func packageErrors(pkg *packages.Package) []error {
+	generated := make(map[string]bool)
+	for i, file := range pkg.Syntax {
+		if ast.IsGenerated(file) {
+			generated[pkg.CompiledGoFiles[i]] = true
+		}
+	}
+	var errs []error
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.TypeError && generated[errorFile(e.Pos)] {
+			continue
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// errorFile returns the file name of the position of a packages.Error, like file.go:12:3 or file.go:12.
+func errorFile(pos string) string {
+	for i := 0; i < 2; i++ {
+		j := strings.LastIndexByte(pos, ':')
+		if j < 0 {
+			break
+		}
+		if _, err := strconv.Atoi(pos[j+1:]); err != nil {
+			break
+		}
+		pos = pos[:j]
+	}
+	return pos
+}
+
+func (l *loader) usePackage(pkg *packages.Package) {
+	l.pkg = pkg
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		l.pkgPathToPackage[p.PkgPath] = p
+	})
+	l.pkgDefaults = fileConfig{}
+	l.methodFields = make(map[token.Pos]*ast.Field)
+	for _, file := range pkg.Syntax {
+		collectMethodFields(file, l.methodFields)
+	}
+	for i, file := range pkg.Syntax {
+		if filepath.Base(pkg.CompiledGoFiles[i]) == pkgDefaultsFile {
+			l.scope = pkg.TypesInfo.Scopes[file]
+			l.pkgDefaults = l.toFileConfig(file.Doc)
+		}
+	}
+}
+
+// pkgDefaultsFile is the file whose defaults are inherited by every file of its package.
+const pkgDefaultsFile = "doc.go"
+
+func (l *loader) loadPackage(filename string, pkg *packages.Package) (*parsedFile, error) {
+	l.usePackage(pkg)
+	var file *ast.File
+	for i, f := range pkg.CompiledGoFiles {
+		if f == filename {
+			file = pkg.Syntax[i]
+			break
+		}
+	}
+	if file == nil {
+		return nil, fmt.Errorf("could not get compiled go file for '%s'", filename)
+	}
+	parsedFile := l.loadFile(filename, file)
+	if err := l.diags.err(); err != nil {
+		return nil, fmt.Errorf("could not load file '%s': %w", filename, err)
+	}
+	return parsedFile, nil
+}
+
+// loadPackageFiles loads the directives of every non-generated file in pkg.
+// Only files declaring at least one wrapped interface or function are returned.
+func (l *loader) loadPackageFiles(pkg *packages.Package) ([]*parsedFile, error) {
+	l.usePackage(pkg)
+	var files []*parsedFile
+	for i, file := range pkg.Syntax {
+		if ast.IsGenerated(file) {
+			continue
+		}
+		pf := l.loadFile(pkg.CompiledGoFiles[i], file)
+		if len(pf.Interfaces) == 0 && len(pf.Functions) == 0 {
+			continue
+		}
+		files = append(files, pf)
+	}
+	if err := l.diags.err(); err != nil {
+		return nil, fmt.Errorf("could not load package '%s': %w", pkg.PkgPath, err)
+	}
+	return files, nil
+}
+
+func (l *loader) loadFile(filename string, file *ast.File) *parsedFile {
+	parsedFile := parsedFile{
+		Filename: filename,
+		Package:  l.pkg.Name,
+		PkgPath:  l.pkg.PkgPath,
+		Scope:    l.pkg.TypesInfo.Scopes[file],
+	}
+	l.scope = parsedFile.Scope
+	l.defaults = l.pkgDefaults
+	if filepath.Base(filename) != pkgDefaultsFile {
+		l.defaults = l.pkgDefaults.merge(l.toFileConfig(file.Doc))
+	}
+
+	for _, d := range file.Decls {
+		switch decl := d.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue // only care about type declarations
+			}
+			if decl.Doc == nil {
+				continue // undocumented types don't count
+			}
+			l.loadGenDecl(&parsedFile, decl)
+		case *ast.FuncDecl:
+			if decl.Doc == nil {
+				continue // undocumented functions don't count
+			}
+			l.loadFuncDecl(&parsedFile, decl)
+		}
+	}
+	return &parsedFile
+}
+
+func (l *loader) loadGenDecl(file *parsedFile, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		l.loadTypeSpec(file, typeSpec, decl.Doc)
+	}
+}
+
+func (l *loader) loadTypeSpec(file *parsedFile, spec *ast.TypeSpec, doc *ast.CommentGroup) {
+	cfg, ok := l.toInterfaceConfig(doc)
+	if !ok {
+		return // not documented with interface marker
+	}
+	l.defaults.applyInterface(&cfg)
+	typeDef, ok := spec.Type.(*ast.InterfaceType)
+	if !ok {
+		l.recordError(spec.Pos(), fmt.Errorf("type is not an interface"))
+		return
+	}
+	iface, err := l.loadInterface(spec, typeDef, cfg)
+	if err != nil {
+		return
+	}
+	file.Interfaces = append(file.Interfaces, iface)
+	return
+}
+
+func (l *loader) loadInterface(spec *ast.TypeSpec, typeDef *ast.InterfaceType, cfg interfaceConfig) (wrappedInterface, error) {
+	var iface wrappedInterface
+	obj, ok := l.pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok {
+		l.recordError(spec.Pos(), fmt.Errorf("could not find type information for '%s'", spec.Name))
+		return iface, fmt.Errorf("unknown type %s", spec.Name)
+	}
+	typ, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		l.recordError(spec.Pos(), fmt.Errorf("type is not an interface"))
+		return iface, fmt.Errorf("type %s is not an interface", spec.Name)
+	}
+	if !typ.IsMethodSet() {
+		l.recordError(spec.Pos(), fmt.Errorf("interface contains type constraints"))
+		return iface, fmt.Errorf("type %s is a constraint interface", spec.Name)
+	}
+	if typ.NumMethods() == 0 {
+		l.recordError(spec.Pos(), fmt.Errorf("interface has no methods"))
+		return iface, fmt.Errorf("interface has no methods")
+	}
+	iface.Name = spec.Name
+	iface.Object = obj
+	iface.Config = cfg
+	if named, ok := obj.Type().(*types.Named); ok {
+		iface.TypeParams = typeParams(named.TypeParams())
+	}
+	for _, method := range interfaceMethods(declaredMethodNames(typeDef), typ) {
+		if err := l.loadInterfaceMethod(&iface, method); err != nil {
+			return iface, err
+		}
+	}
+	return iface, nil
+}
+
+// interfaceMethods returns the full method set of the interface typ.
+// Methods declared directly in typeDef come first in source order, followed
+// by the methods promoted from embedded interfaces sorted by name.
+func interfaceMethods(declared []string, typ *types.Interface) []*types.Func {
+	byName := make(map[string]*types.Func, typ.NumMethods())
+	for i := 0; i < typ.NumMethods(); i++ {
+		m := typ.Method(i)
+		byName[m.Name()] = m
+	}
+	methods := make([]*types.Func, 0, len(byName))
+	for _, name := range declared {
+		if m, ok := byName[name]; ok {
+			methods = append(methods, m)
+			delete(byName, name)
+		}
+	}
+	for i := 0; i < typ.NumMethods(); i++ { // sorted by go/types
+		if m, ok := byName[typ.Method(i).Name()]; ok {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// declaredMethodNames returns the names of the methods declared directly in the interface.
+func declaredMethodNames(typeDef *ast.InterfaceType) []string {
+	if typeDef.Methods == nil {
+		return nil
+	}
+	var names []string
+	for _, field := range typeDef.Methods.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// collectMethodFields adds the method fields of the interface types of file to fields, by the position of their names,
+// so that directives on methods promoted from embedded interfaces or of optional interfaces can be found.
+func collectMethodFields(file *ast.File, fields map[token.Pos]*ast.Field) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		it, ok := n.(*ast.InterfaceType)
+		if !ok || it.Methods == nil {
+			return true
+		}
+		for _, field := range it.Methods.List {
+			for _, name := range field.Names {
+				fields[name.Pos()] = field
+			}
+		}
+		return true
+	})
+}
+
+// fileScope returns the scope of the file of the package being loaded containing pos, or nil.
+func (l *loader) fileScope(pos token.Pos) *types.Scope {
+	for _, file := range l.pkg.Syntax {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			return l.pkg.TypesInfo.Scopes[file]
+		}
+	}
+	return nil
+}
+
+func typeParams(list *types.TypeParamList) []typeParam {
+	if list.Len() == 0 {
+		return nil
+	}
+	params := make([]typeParam, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		tp := list.At(i)
+		params = append(params, typeParam{
+			Name:       tp.Obj().Name(),
+			Constraint: tp.Constraint(),
+		})
+	}
+	return params
+}
+
+func (l *loader) loadInterfaceMethod(iface *wrappedInterface, method *types.Func) error {
+	fn := l.loadFunction(iface, method, l.methodConfig(method))
+	if fn.Name == nil {
+		return fmt.Errorf("failed to load function '%s'", method.Name())
+	}
+	iface.Functions = append(iface.Functions, fn)
+	return nil
+}
+
+// methodConfig returns the directives of the interface method m, parsed in the scope of the file declaring it,
+// which are only known when m is declared in the package being loaded.
+func (l *loader) methodConfig(m *types.Func) functionConfig {
+	field, ok := l.methodFields[m.Pos()]
+	if !ok {
+		return functionConfig{}
+	}
+	scope := l.scope
+	defer func() { l.scope = scope }()
+	l.scope = l.fileScope(m.Pos())
+	cfg, _ := l.toFunctionConfig(field.Doc)
+	return cfg
+}
+
+func (l *loader) loadFunction(iface *wrappedInterface, fn *types.Func, cfg functionConfig) (fun wrappedFunction) {
+	fun.Name = &ast.Ident{Name: fn.Name(), NamePos: fn.Pos()}
+	fun.Object = fn
+	fun.Config = cfg
+	if iface != nil {
+		fun.Config.AttributeFunctions = mergeAttrs(iface.Config.AttributeFunctions, cfg.AttributeFunctions)
+		fun.Config.Setters = iface.Config.Setters
+		fun.Config.Consts = mergeByKey(iface.Config.Consts, cfg.Consts, func(c constAttr) string { return c.Key })
+		fun.Config.ContextAttrs = mergeByKey(iface.Config.ContextAttrs, cfg.ContextAttrs, func(ca *ctxAttr) string { return ca.Key })
+		if fun.Config.ErrClass == nil {
+			fun.Config.ErrClass = iface.Config.ErrClass
+		}
+	}
+	if fun.Config.OperationName == "" {
+		fun.Config.OperationName = l.operationName(iface, fn)
+	}
+	sig := fn.Type().(*types.Signature)
+	fun.TypeParams = typeParams(sig.TypeParams())
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := sig.Params().At(i)
+		fun.Arguments = append(fun.Arguments, funcArg{
+			Name:     p.Name(),
+			Type:     p.Type(),
+			Pos:      p.Pos(),
+			Variadic: sig.Variadic() && i == sig.Params().Len()-1,
+		})
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		p := sig.Results().At(i)
+		fun.Returns = append(fun.Returns, funcArg{Name: p.Name(), Type: p.Type(), Pos: p.Pos()})
+	}
+	return fun
+}
+
+// defaultOpFormat is the operation name template used without an OperationFormat or opformat directive.
+const defaultOpFormat = "{{.Package}}{{with .Interface}}.{{.}}{{end}}:{{.Method}}"
+
+var defaultOpFormatTemplate = template.Must(parseOpFormat(defaultOpFormat))
+
+func parseOpFormat(text string) (*template.Template, error) {
+	return template.New("opformat").Funcs(funcMap).Parse(text)
+}
+
+// operationName returns the default operation name of fn, a method of iface,
+// or a package-level function if iface is nil.
+func (l *loader) operationName(iface *wrappedInterface, fn *types.Func) string {
+	data := OperationNameData{
+		PkgPath: l.pkg.PkgPath,
+		Package: l.pkg.Name,
+		Method:  fn.Name(),
+	}
+	format := l.defaults.OperationFormat
+	if iface != nil {
+		data.Interface = iface.Name.Name
+		for _, tp := range iface.TypeParams {
+			data.TypeParams = append(data.TypeParams, tp.Name)
+		}
+		format = iface.Config.OperationFormat
+	}
+	if format == nil {
+		format = l.opFormat
+	}
+	if format == nil {
+		format = defaultOpFormatTemplate
+	}
+	var b strings.Builder
+	if err := format.Execute(&b, data); err != nil {
+		l.recordError(fn.Pos(), fmt.Errorf("opformat: %w", err))
+	}
+	return b.String()
+}
+
+// lookupObject resolves an identifier or package-qualified selector written in a directive
+// using the scope of the file containing it.
+func (l *loader) lookupObject(scope *types.Scope, expr ast.Expr) (types.Object, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, obj := scope.LookupParent(e.Name, token.NoPos); obj != nil {
+			return obj, nil
+		}
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		pkgName, ok := scope.Lookup(x.Name).(*types.PkgName)
+		if !ok {
+			return nil, fmt.Errorf("package %s is not imported", x.Name)
+		}
+		if obj := pkgName.Imported().Scope().Lookup(e.Sel.Name); obj != nil && obj.Exported() {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("undefined: %s", types.ExprString(expr))
+}
+
+func (l *loader) recordError(pos token.Pos, err error) {
+	l.diags = append(l.diags, Diagnostic{
+		Pos: l.fset.Position(pos),
+		Err: err,
+	})
+}
+
+func (l *loader) loadFuncDecl(file *parsedFile, decl *ast.FuncDecl) {
+	fcfg, ok := l.toFunctionConfig(decl.Doc)
+	if !ok {
+		return
+	}
+	l.defaults.applyFunction(&fcfg)
+	obj, ok := l.pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		l.recordError(decl.Pos(), fmt.Errorf("could not find type information for '%s'", decl.Name))
+		return
+	}
+	fun := l.loadFunction(nil, obj, fcfg)
+	if fun.Name != nil {
+		file.Functions = append(file.Functions, fun)
+	}
+}
+
+func (l *loader) typeIsContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+func (l *loader) typeIsError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,122 @@
+package gen
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// maxOptional is the maximum number of optional interfaces of a wrapped interface,
+// since the constructor handles every combination of them.
+const maxOptional = 6
+
+// optionalTypes creates the template data of the optional interfaces of iface, wrapped by wi
+// with the instrumentation in. The names of their types are added to the reserved type names.
+func (l *loader) optionalTypes(file *parsedFile, iface wrappedInterface, wi TemplateTypeConfig, in instrumentation, it *typeImporter, cache *autoSetterFuncCache) ([]TemplateOptionalType, error) {
+	if len(iface.Config.Optional) > maxOptional {
+		l.recordError(iface.Config.Optional[maxOptional].Pos, fmt.Errorf("optional: at most %d optional interfaces are supported", maxOptional))
+		return nil, nil
+	}
+	// methods maps the name of every wrapped method to the interface declaring it.
+	methods := make(map[string]*types.TypeName)
+	for _, f := range iface.Functions {
+		methods[f.Name.Name] = iface.Object
+	}
+	var optional []TemplateOptionalType
+	for i, oc := range iface.Config.Optional {
+		obj, err := l.lookupObject(file.Scope, oc.Type)
+		if err != nil {
+			l.recordError(oc.Pos, fmt.Errorf("optional: %w", err))
+			continue
+		}
+		tn, ok := obj.(*types.TypeName)
+		var typ *types.Interface
+		if ok {
+			typ, ok = tn.Type().Underlying().(*types.Interface)
+		}
+		if !ok || !typ.IsMethodSet() {
+			l.recordError(oc.Pos, fmt.Errorf("optional: %s is not an interface", obj.Name()))
+			continue
+		}
+		if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			l.recordError(oc.Pos, fmt.Errorf("optional: generic interface %s is not supported", tn.Name()))
+			continue
+		}
+		var o TemplateOptionalType
+		o.Name = tn.Name()
+		o.QualifiedName = it.objectString(tn)
+		o.TypeName = uniqueTypeName(l.typeNames, wi.TypeName+tn.Name())
+		o.NoTrace = wi.NoTrace
+		o.Metrics = wi.Metrics
+		o.Log = wi.Log
+		o.LoggerType = wi.LoggerType
+		o.Var = fmt.Sprintf("opt%d", i)
+		o.OKVar = fmt.Sprintf("ok%d", i)
+		for _, m := range interfaceMethods(nil, typ) {
+			if prev, ok := methods[m.Name()]; ok {
+				if prev == iface.Object && sameMethod(iface, m) {
+					continue // already wrapped
+				}
+				l.recordError(oc.Pos, fmt.Errorf("optional: method %s of %s is also in %s", m.Name(), tn.Name(), prev.Name()))
+				continue
+			}
+			methods[m.Name()] = tn
+			f := l.loadFunction(&iface, m, l.methodConfig(m))
+			l.checkMethodInstrumentation(f, in, wi.Name)
+			fun, err := l.createWrapperFunction(file, f, in, it, cache)
+			if err != nil {
+				return nil, err
+			}
+			o.Functions = append(o.Functions, fun)
+		}
+		if len(o.Functions) == 0 {
+			l.recordError(oc.Pos, fmt.Errorf("optional: %s has no methods besides those of %s", tn.Name(), wi.Name))
+			continue
+		}
+		optional = append(optional, o)
+	}
+	return optional, nil
+}
+
+// uniqueTypeName returns name, or name with the lowest number suffix not in typeNames, and adds it to typeNames,
+// since optional interfaces of different packages may have the same name, or the name of a wrapper type
+// may be that of another wrapper type followed by the name of one of its optional interfaces.
+func uniqueTypeName(typeNames map[string]struct{}, name string) string {
+	unique := name
+	for n := 2; ; n++ {
+		if _, ok := typeNames[unique]; !ok {
+			break
+		}
+		unique = fmt.Sprintf("%s%d", name, n)
+	}
+	typeNames[unique] = struct{}{}
+	return unique
+}
+
+// sameMethod reports whether the wrapped interface has a method identical to m.
+func sameMethod(iface wrappedInterface, m *types.Func) bool {
+	for _, f := range iface.Functions {
+		if f.Name.Name == m.Name() {
+			return types.Identical(f.Object.Type(), m.Type())
+		}
+	}
+	return false
+}
+
+// subsets returns the non-empty subsets of the optional interfaces, largest first.
+func subsets(optional []TemplateOptionalType) [][]TemplateOptionalType {
+	var sets [][]TemplateOptionalType
+	for size := len(optional); size > 0; size-- {
+		for mask := 1; mask < 1<<len(optional); mask++ {
+			var set []TemplateOptionalType
+			for i := range optional {
+				if mask&(1<<i) != 0 {
+					set = append(set, optional[i])
+				}
+			}
+			if len(set) == size {
+				sets = append(sets, set)
+			}
+		}
+	}
+	return sets
+}
@@ -0,0 +1,79 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteOutput writes the generated content to the output file, creating its directory if needed.
+// The file is replaced atomically, and is not touched at all when its content is already up-to-date.
+func (r *Result) WriteOutput() error {
+	current, err := os.ReadFile(r.OutputFile)
+	if err == nil && bytes.Equal(current, r.Content) {
+		return nil
+	}
+	dir := filepath.Dir(r.OutputFile)
+	if err = os.MkdirAll(dir, os.FileMode(0o755)); err != nil {
+		return fmt.Errorf("could not create directory %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(r.OutputFile)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err = tmp.Write(r.Content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), r.OutputFile)
+}
+
+// writeDebugSource writes the unformatted source for outFile to the temp directory,
+// with the formatter errors annotated below the lines they refer to.
+// It returns the name of the file written.
+func writeDebugSource(outFile string, src []byte, fmtErr error) (string, error) {
+	f, err := os.CreateTemp("", "genstrument-"+filepath.Base(outFile)+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Write(annotateSource(src, fmtErr)); err != nil {
+		f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// annotateSource adds a comment below each line of src with an error in fmtErr.
+func annotateSource(src []byte, fmtErr error) []byte {
+	var list scanner.ErrorList
+	if !errors.As(fmtErr, &list) {
+		return append([]byte("// "+fmtErr.Error()+"\n"), src...)
+	}
+	errs := make(map[int][]*scanner.Error)
+	for _, e := range list {
+		errs[e.Pos.Line] = append(errs[e.Pos.Line], e)
+	}
+	var buf bytes.Buffer
+	for i, line := range strings.SplitAfter(string(src), "\n") {
+		buf.WriteString(line)
+		for _, e := range errs[i+1] {
+			if !strings.HasSuffix(line, "\n") {
+				buf.WriteByte('\n')
+			}
+			fmt.Fprintf(&buf, "%s^ genstrument: format error: %s\n", strings.Repeat(" ", max(e.Pos.Column-1, 0)), e.Msg)
+		}
+	}
+	return buf.Bytes()
+}
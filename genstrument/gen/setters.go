@@ -0,0 +1,286 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// resolvedSetter is a setter directive resolved in the scope of its file.
+// Func is nil if the directive is invalid.
+type resolvedSetter struct {
+	Type types.Type
+	Func *types.Func
+}
+
+// registeredSetter returns the setter function of the first setter directive registered for the type t, or nil.
+func (l *loader) registeredSetter(setters []*setterConfig, t types.Type) *types.Func {
+	for _, sc := range setters {
+		if rs := l.resolveSetter(sc); rs.Func != nil && types.Identical(rs.Type, t) {
+			return rs.Func
+		}
+	}
+	return nil
+}
+
+// resolveSetters resolves every setter directive, recording the invalid ones.
+func (l *loader) resolveSetters(setters []*setterConfig) {
+	for _, sc := range setters {
+		l.resolveSetter(sc)
+	}
+}
+
+// resolveSetter resolves the setter directive sc. Each directive is resolved and validated once.
+func (l *loader) resolveSetter(sc *setterConfig) resolvedSetter {
+	if rs, ok := l.setters[sc]; ok {
+		return rs
+	}
+	rs, err := l.validateSetter(sc)
+	if err != nil {
+		l.recordError(sc.Pos, fmt.Errorf("setter: %w", err))
+		rs.Func = nil
+	}
+	l.setters[sc] = rs
+	return rs
+}
+
+// validateSetter resolves the type and function of sc, and checks that the function
+// is a func(T, genstrument.AttributeSetter) accepting the type.
+func (l *loader) validateSetter(sc *setterConfig) (rs resolvedSetter, err error) {
+	obj, err := l.lookupObject(sc.Scope, sc.Type)
+	if err != nil {
+		return rs, err
+	}
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return rs, fmt.Errorf("%s is not a type", obj.Name())
+	}
+	rs.Type = tn.Type()
+	if obj, err = l.lookupObject(sc.Scope, sc.Func); err != nil {
+		return rs, err
+	}
+	if rs.Func, ok = obj.(*types.Func); !ok {
+		return rs, fmt.Errorf("%s is not a function", obj.Name())
+	}
+	attrSetter, err := l.runtimeType("AttributeSetter")
+	if err != nil {
+		return rs, err
+	}
+	sig := rs.Func.Type().(*types.Signature)
+	if sig.Params().Len() != 2 || sig.Results().Len() != 0 || !types.Identical(sig.Params().At(1).Type(), attrSetter) {
+		return rs, fmt.Errorf("%s is not a func(%s, genstrument.AttributeSetter)", rs.Func.Name(), tn.Name())
+	}
+	param := sig.Params().At(0).Type()
+	if tparams := sig.TypeParams(); tparams.Len() > 0 {
+		if tparams.Len() != 1 || param != tparams.At(0) {
+			return rs, fmt.Errorf("generic %s must have a single type parameter, the type of its first parameter", rs.Func.Name())
+		}
+		if _, err := types.Instantiate(nil, sig, []types.Type{rs.Type}, true); err != nil {
+			return rs, fmt.Errorf("%s does not satisfy the type constraint of %s", tn.Name(), rs.Func.Name())
+		}
+		return rs, nil
+	}
+	if !types.AssignableTo(rs.Type, param) {
+		return rs, fmt.Errorf("%s cannot be passed to %s", tn.Name(), rs.Func.Name())
+	}
+	return rs, nil
+}
+
+// autoSetter is a setter of the runtime package used for the argument types it matches.
+type autoSetter struct {
+	name  string
+	match func(t types.Type) bool
+}
+
+// autoSetterFuncCache finds the setter of attribute arguments without a setter function.
+type autoSetterFuncCache struct {
+	// ranks are the auto-setters from the most specific to the least specific;
+	// the first rank with a matching setter is used, and more than one match in it is ambiguous.
+	ranks [][]autoSetter
+}
+
+func newAutoSetterFuncCache(it *typeImporter, runtime *types.Package) *autoSetterFuncCache {
+	var cache autoSetterFuncCache
+	lookup := func(name string) *types.Func {
+		fn, _ := runtime.Scope().Lookup(name).(*types.Func)
+		return fn
+	}
+	setter := func(name string) string {
+		if fn := lookup(name); fn != nil {
+			return it.objectString(fn)
+		}
+		return ""
+	}
+	// firstParam returns the type of the first parameter of a setter, like time.Time or fmt.Stringer.
+	firstParam := func(name string) types.Type {
+		if fn := lookup(name); fn != nil {
+			if params := fn.Type().(*types.Signature).Params(); params.Len() > 0 {
+				return params.At(0).Type()
+			}
+		}
+		return nil
+	}
+	var (
+		timeType     = firstParam("SetTimeAttribute")
+		durationType = firstParam("SetDurationAttribute")
+		errorType    = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+		stringerType *types.Interface
+		marshaler    *types.Interface
+	)
+	if t := firstParam("SetStringerAttribute"); t != nil {
+		stringerType, _ = t.Underlying().(*types.Interface)
+	}
+	if obj := runtime.Scope().Lookup("AttributeMarshaler"); obj != nil {
+		marshaler, _ = obj.Type().Underlying().(*types.Interface)
+	}
+	identical := func(typ types.Type) func(t types.Type) bool {
+		return func(t types.Type) bool {
+			return typ != nil && types.Identical(t, typ)
+		}
+	}
+	implements := func(iface *types.Interface) func(t types.Type) bool {
+		return func(t types.Type) bool {
+			return iface != nil && !isPointer(t) && types.Implements(t, iface)
+		}
+	}
+	pointerImplements := func(iface *types.Interface) func(t types.Type) bool {
+		return func(t types.Type) bool {
+			return iface != nil && isPointer(t) && types.Implements(t, iface)
+		}
+	}
+	// addrImplements matches the types whose pointer, but not the type itself, implements iface.
+	addrImplements := func(iface *types.Interface) func(t types.Type) bool {
+		return func(t types.Type) bool {
+			if iface == nil || isPointer(t) || types.Implements(t, iface) {
+				return false
+			}
+			_, isInterface := t.Underlying().(*types.Interface)
+			return !isInterface && types.Implements(types.NewPointer(t), iface)
+		}
+	}
+	ranks := [][]autoSetter{
+		// AttributeMarshaler ranks first, since a type implementing it chose the attributes it sets,
+		// like a string type masking its value.
+		{
+			{name: "SetMarshalerAttribute", match: implements(marshaler)},
+			{name: "SetMarshalerPointerAttribute", match: pointerImplements(marshaler)},
+			{name: "SetMarshalerAddrAttribute", match: addrImplements(marshaler)},
+		},
+		{
+			{name: "SetTimeAttribute", match: identical(timeType)},
+			{name: "SetDurationAttribute", match: identical(durationType)},
+			{name: "SetTimePointerAttribute", match: pointerTo(identical(timeType))},
+			{name: "SetDurationPointerAttribute", match: pointerTo(identical(durationType))},
+		},
+		{
+			{name: "SetStringAttribute", match: basicInfo(isString)},
+			{name: "SetIntAttribute", match: basicInfo(isInt)},
+			{name: "SetUintAttribute", match: basicInfo(isUint)},
+			{name: "SetBoolAttribute", match: basicInfo(isBool)},
+			{name: "SetFloatAttribute", match: basicInfo(isFloat)},
+			{name: "SetStringPointerAttribute", match: pointerTo(basicInfo(isString))},
+			{name: "SetIntPointerAttribute", match: pointerTo(basicInfo(isInt))},
+			{name: "SetUintPointerAttribute", match: pointerTo(basicInfo(isUint))},
+			{name: "SetBoolPointerAttribute", match: pointerTo(basicInfo(isBool))},
+			{name: "SetFloatPointerAttribute", match: pointerTo(basicInfo(isFloat))},
+		},
+		{
+			{name: "SetErrorAttribute", match: implements(errorType)},
+			{name: "SetStringerAttribute", match: implements(stringerType)},
+			{name: "SetErrorPointerAttribute", match: pointerImplements(errorType)},
+			{name: "SetStringerPointerAttribute", match: pointerImplements(stringerType)},
+		},
+		{
+			{name: "SetBytesAttribute", match: sliceOf(basicInfo(isByte))},
+			{name: "SetStringSliceAttribute", match: sliceOf(basicInfo(isString))},
+			{name: "SetIntSliceAttribute", match: sliceOf(basicInfo(isInt))},
+			{name: "SetBoolSliceAttribute", match: sliceOf(basicInfo(isBool))},
+			{name: "SetFloatSliceAttribute", match: sliceOf(basicInfo(isFloat))},
+		},
+	}
+	for _, rank := range ranks {
+		var available []autoSetter
+		for _, s := range rank {
+			if name := setter(s.name); name != "" {
+				available = append(available, autoSetter{name: name, match: s.match})
+			}
+		}
+		cache.ranks = append(cache.ranks, available)
+	}
+	return &cache
+}
+
+// autoSetterFunc returns the setter of the type t, or an error if none or more than one setter of the same rank matches it.
+func (c *autoSetterFuncCache) autoSetterFunc(t types.Type) (string, error) {
+	for _, rank := range c.ranks {
+		var candidates []string
+		for _, s := range rank {
+			if s.match(t) {
+				candidates = append(candidates, s.name)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		default:
+			return "", fmt.Errorf("ambiguous auto-setter functions %s", strings.Join(candidates, ", "))
+		}
+	}
+	return "", errors.New("cannot find auto-setter function")
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+// pointerTo matches pointer types whose element type matches.
+func pointerTo(match func(t types.Type) bool) func(t types.Type) bool {
+	return func(t types.Type) bool {
+		p, ok := t.(*types.Pointer)
+		return ok && match(p.Elem())
+	}
+}
+
+// sliceOf matches slice types whose element type matches.
+func sliceOf(match func(t types.Type) bool) func(t types.Type) bool {
+	return func(t types.Type) bool {
+		s, ok := t.Underlying().(*types.Slice)
+		return ok && match(s.Elem())
+	}
+}
+
+// basicInfo matches types whose underlying type is a basic type matching is.
+func basicInfo(is func(b *types.Basic) bool) func(t types.Type) bool {
+	return func(t types.Type) bool {
+		b, ok := t.Underlying().(*types.Basic)
+		return ok && b.Info()&types.IsUntyped == 0 && is(b)
+	}
+}
+
+func isString(b *types.Basic) bool {
+	return b.Info()&types.IsString != 0
+}
+
+func isInt(b *types.Basic) bool {
+	return b.Info()&types.IsInteger != 0 && b.Info()&types.IsUnsigned == 0
+}
+
+func isUint(b *types.Basic) bool {
+	return b.Info()&types.IsUnsigned != 0
+}
+
+func isBool(b *types.Basic) bool {
+	return b.Info()&types.IsBoolean != 0
+}
+
+func isFloat(b *types.Basic) bool {
+	return b.Info()&types.IsFloat != 0
+}
+
+func isByte(b *types.Basic) bool {
+	return b.Kind() == types.Uint8
+}
@@ -0,0 +1,264 @@
+package gen
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// funcMap holds the functions available to the built-in and custom templates.
+var funcMap = map[string]interface{}{
+	"quote":             strconv.Quote,
+	"lower":             strings.ToLower,
+	"upper":             strings.ToUpper,
+	"title":             title,
+	"join":              strings.Join,
+	"contains":          strings.Contains,
+	"has_prefix":        strings.HasPrefix,
+	"has_suffix":        strings.HasSuffix,
+	"trim_prefix":       strings.TrimPrefix,
+	"trim_suffix":       strings.TrimSuffix,
+	"replace":           strings.ReplaceAll,
+	"join_and":          joinAnd,
+	"instruments":       instruments,
+	"instrument_params": instrumentParams,
+	"subsets":           subsets,
+	"method_data": func(t TemplateTypeConfig, f TemplateFunctionConfig) TemplateMethodData {
+		return TemplateMethodData{Type: t, Function: f}
+	},
+	"function_data": func(f TemplateFunctionConfig) TemplateMethodData {
+		return TemplateMethodData{Function: f}
+	},
+	"arg_names": func(args []TemplateFunctionArg) []string {
+		names := make([]string, 0, len(args))
+		for _, a := range args {
+			names = append(names, a.Name)
+		}
+		return names
+	},
+	"arg_types": func(args []TemplateFunctionArg) []string {
+		typs := make([]string, 0, len(args))
+		for _, a := range args {
+			typs = append(typs, a.Type)
+		}
+		return typs
+	},
+	"attr_args": func(args []TemplateFunctionArg) []TemplateFunctionArg {
+		var attrs []TemplateFunctionArg
+		for _, a := range args {
+			if a.AttrKey != "" {
+				attrs = append(attrs, a)
+			}
+		}
+		return attrs
+	},
+	"field_attr": fieldAttr,
+	"set_attrs": func(target, mode string, argLists ...[]TemplateFunctionArg) string {
+		return setAttrs(target, mode, false, argLists)
+	},
+	"set_metric_attrs": func(target, mode string, argLists ...[]TemplateFunctionArg) string {
+		return setAttrs(target, mode, true, argLists)
+	},
+	"finish_attrs": finishAttrs,
+	"const_attr": func(c TemplateConstAttr, target string) string {
+		return fmt.Sprintf("%s.Attribute(%q).%s(%s)", target, c.AttrKey, c.Method, c.Value)
+	},
+	"ctx_attr": func(c TemplateContextAttr, ctx, target string) string {
+		if !c.HasOK {
+			return fmt.Sprintf("%s(%s(%s),%s.Attribute(%q))", c.AttrFunc, c.Func, ctx, target, c.AttrKey)
+		}
+		return fmt.Sprintf("if v, ok := %s(%s); ok {\n%s(v,%s.Attribute(%q))\n}", c.Func, ctx, c.AttrFunc, target, c.AttrKey)
+	},
+	"arg_list": func(wf TemplateFunctionConfig) string {
+		arglist := make([]string, 0, len(wf.Arguments))
+		for _, a := range wf.Arguments {
+			arglist = append(arglist, fmt.Sprintf("%s %s", a.Name, a.Type))
+		}
+		return strings.Join(arglist, ",")
+	},
+	"return_list": func(wf TemplateFunctionConfig) string {
+		if len(wf.Returns) == 0 {
+			return ""
+		}
+		arglist := make([]string, 0, len(wf.Returns))
+		for _, r := range wf.Returns {
+			arglist = append(arglist, fmt.Sprintf("%s %s", r.Name, r.Type))
+		}
+		return "(" + strings.Join(arglist, ",") + ")"
+	},
+	"call_list": func(wf TemplateFunctionConfig) string {
+		arglist := make([]string, 0, len(wf.Arguments))
+		for _, a := range wf.Arguments {
+			if a.Variadic {
+				arglist = append(arglist, a.Name+"...")
+				continue
+			}
+			arglist = append(arglist, a.Name)
+		}
+		return strings.Join(arglist, ",")
+	},
+	"assign_result_list": func(wf TemplateFunctionConfig) string {
+		if len(wf.Returns) == 0 {
+			return ""
+		}
+		arglist := make([]string, 0, len(wf.Returns))
+		for _, r := range wf.Returns {
+			arglist = append(arglist, r.Name)
+		}
+		return strings.Join(arglist, ",") + " = "
+	},
+}
+
+// title upper-cases the first letter of s.
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// joinAnd joins the words of a list like "a, b and c".
+func joinAnd(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// instruments lists what a TemplateTypeConfig or TemplateFunctionConfig records, like "APM traces".
+func instruments(v interface{}) ([]string, error) {
+	var noTrace, metrics, log bool
+	switch c := v.(type) {
+	case TemplateTypeConfig:
+		noTrace, metrics, log = c.NoTrace, c.Metrics, c.Log
+	case TemplateFunctionConfig:
+		noTrace, metrics, log = c.NoTrace, c.Metrics, c.Log
+	default:
+		return nil, fmt.Errorf("instruments: unexpected %T", v)
+	}
+	var kinds []string
+	if !noTrace {
+		kinds = append(kinds, "APM traces")
+	}
+	if metrics {
+		kinds = append(kinds, "metrics")
+	}
+	if log {
+		kinds = append(kinds, "logs")
+	}
+	return kinds, nil
+}
+
+// instrumentParams lists the instrumentation parameters of the constructor of a TemplateTypeConfig,
+// like "tracer", or of the wrapper of a TemplateFunctionConfig, like "tracer tr".
+func instrumentParams(v interface{}) ([]string, error) {
+	var params []string
+	switch c := v.(type) {
+	case TemplateTypeConfig:
+		if !c.NoTrace {
+			params = append(params, "tracer")
+		}
+		if c.Metrics {
+			params = append(params, "meter")
+		}
+		if c.Log {
+			params = append(params, "logger")
+		}
+	case TemplateFunctionConfig:
+		if !c.NoTrace {
+			params = append(params, "tracer "+c.TracerArg)
+		}
+		if c.Metrics {
+			params = append(params, "meter "+c.MeterArg)
+		}
+		if c.Log {
+			params = append(params, "logger "+c.LoggerArg)
+		}
+	default:
+		return nil, fmt.Errorf("instrument_params: unexpected %T", v)
+	}
+	return params, nil
+}
+
+// generateOutput renders the file template "template.tmpl" for exp.
+// The templateFiles and then text are parsed on top of the built-in templates, so they can
+// redefine any of the named templates, or replace the whole file when they have content
+// outside of {{define}} blocks.
+func generateOutput(exp TemplateData, templateFiles []string, text string, w io.Writer) error {
+	t, err := template.New("").Funcs(funcMap).ParseFS(templatesFS, "templates/*")
+	if err != nil {
+		return err
+	}
+	for _, name := range templateFiles {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("template %s: %w", name, err)
+		}
+		if t, err = t.New("template.tmpl").Parse(string(b)); err != nil {
+			return fmt.Errorf("template %s parse: %w", name, err)
+		}
+	}
+	if text != "" {
+		if t, err = t.New("template.tmpl").Parse(text); err != nil {
+			return fmt.Errorf("template parse: %w", err)
+		}
+	}
+	if err = t.ExecuteTemplate(w, "template.tmpl", exp); err != nil {
+		return fmt.Errorf("template execute: %w", err)
+	}
+	return nil
+}
+
+// fieldAttr renders the setter call of the field attribute fa on target, nested in its guards.
+func fieldAttr(fa TemplateFieldAttr, target string) string {
+	call := fmt.Sprintf("%s(%s,%s.Attribute(%q))", fa.AttrFunc, fa.Value, target, fa.AttrKey)
+	for i := len(fa.Guards) - 1; i >= 0; i-- {
+		call = fmt.Sprintf("if %s {\n%s\n}", fa.Guards[i], call)
+	}
+	return call
+}
+
+// setAttrs renders the setter calls on target of the attributes with the mode of the arguments and their fields,
+// or only those of the metric attributes.
+func setAttrs(target, mode string, metricsOnly bool, argLists [][]TemplateFunctionArg) string {
+	var calls []string
+	for _, args := range argLists {
+		for _, a := range args {
+			if a.AttrFunc != "" && a.AttrMode == mode && (!metricsOnly || a.MetricAttr) {
+				calls = append(calls, fmt.Sprintf("%s(%s,%s.Attribute(%q))", a.AttrFunc, a.Name, target, a.AttrKey))
+			}
+			for _, fa := range a.Fields {
+				if fa.AttrMode == mode && (!metricsOnly || fa.MetricAttr) {
+					calls = append(calls, fieldAttr(fa, target))
+				}
+			}
+		}
+	}
+	return strings.Join(calls, "\n")
+}
+
+// finishAttrs renders the setter calls on target of the attributes set after the call of f returns: those of
+// the results with the mode always, then those with the modes on-error and on-success depending on its error.
+func finishAttrs(f TemplateFunctionConfig, target string, metricsOnly bool) string {
+	calls := []string{setAttrs(target, attrAlways, metricsOnly, [][]TemplateFunctionArg{f.Returns})}
+	onError := setAttrs(target, attrOnError, metricsOnly, [][]TemplateFunctionArg{f.Arguments, f.Returns})
+	onSuccess := setAttrs(target, attrOnSuccess, metricsOnly, [][]TemplateFunctionArg{f.Arguments, f.Returns})
+	switch {
+	case f.ErrorReturn == "":
+		calls = append(calls, onSuccess)
+	case onError != "" && onSuccess != "":
+		calls = append(calls, fmt.Sprintf("if %s != nil {\n%s\n} else {\n%s\n}", f.ErrorReturn, onError, onSuccess))
+	case onError != "":
+		calls = append(calls, fmt.Sprintf("if %s != nil {\n%s\n}", f.ErrorReturn, onError))
+	case onSuccess != "":
+		calls = append(calls, fmt.Sprintf("if %s == nil {\n%s\n}", f.ErrorReturn, onSuccess))
+	}
+	return strings.TrimSpace(strings.Join(calls, "\n"))
+}
@@ -0,0 +1,17 @@
+// Package crossfile wraps an interface embedding one declared in another file,
+// whose method directives name functions of packages imported by that file only.
+package crossfile
+
+import (
+	"context"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// Base is embedded by Service.
+type Base interface {
+	// +genstrument:attr key key genstrument.SetStringAttribute
+	Get(ctx context.Context, key string) error
+}
+
+var _ genstrument.AttributeSetter
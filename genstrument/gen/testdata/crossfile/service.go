@@ -0,0 +1,11 @@
+package crossfile
+
+import "context"
+
+// Service
+//
+// +genstrument:wrap
+type Service interface {
+	Base
+	Put(ctx context.Context, key string) error
+}
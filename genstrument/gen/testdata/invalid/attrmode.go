@@ -0,0 +1,12 @@
+package invalid
+
+import "context"
+
+// AttrModes sets an attribute on error of a method without an error result.
+//
+// +genstrument:wrap
+type AttrModes interface {
+	// +genstrument:attr count n always
+	// +genstrument:attr failed.key key on-error
+	Count(ctx context.Context, key string) (n int)
+}
@@ -0,0 +1,11 @@
+package invalid
+
+import "context"
+
+// AttrModeSyntax has an attr directive with an unknown mode.
+//
+// +genstrument:wrap
+// +genstrument:attr key key genstrument.SetStringAttribute sometimes
+type AttrModeSyntax interface {
+	Get(ctx context.Context, key string) error
+}
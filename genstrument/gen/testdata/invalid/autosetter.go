@@ -0,0 +1,21 @@
+package invalid
+
+import (
+	"context"
+	"fmt"
+)
+
+type Status struct{}
+
+func (Status) Error() string  { return "status" }
+func (Status) String() string { return "status" }
+
+// AutoSetters has arguments without an auto-setter.
+//
+// +genstrument:wrap
+type AutoSetters interface {
+	// +genstrument:attr status status
+	Ambiguous(ctx context.Context, status Status) error
+	// +genstrument:attr values values
+	Unsupported(ctx context.Context, values []fmt.Stringer) error
+}
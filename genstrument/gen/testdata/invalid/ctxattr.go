@@ -0,0 +1,22 @@
+package invalid
+
+import "context"
+
+func NoContext(s string) string { return s }
+
+func Maybe(ctx context.Context) (string, error) { return "", nil }
+
+type Tenant struct{}
+
+func TenantOf(ctx context.Context) Tenant { return Tenant{} }
+
+// ContextAttrs has invalid const and ctxattr directives.
+//
+// +genstrument:wrap
+// +genstrument:ctxattr missing Missing
+// +genstrument:ctxattr no.context NoContext
+// +genstrument:ctxattr maybe Maybe
+// +genstrument:ctxattr tenant TenantOf
+type ContextAttrs interface {
+	Get(ctx context.Context) error
+}
@@ -0,0 +1,12 @@
+package invalid
+
+import "context"
+
+// ContextAttrSyntax has const and ctxattr directives with missing arguments.
+//
+// +genstrument:wrap
+// +genstrument:const key
+// +genstrument:ctxattr tenant
+type ContextAttrSyntax interface {
+	Get(ctx context.Context) error
+}
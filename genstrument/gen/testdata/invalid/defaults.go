@@ -0,0 +1,4 @@
+// +genstrument:defaults
+// +genstrument:metrics region
+// +genstrument:wrap
+package invalid
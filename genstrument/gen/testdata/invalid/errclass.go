@@ -0,0 +1,23 @@
+package invalid
+
+import "context"
+
+func ClassifyString(err error) string { return "" }
+
+var NotAFunc = 1
+
+// ErrClasses has invalid errclass directives.
+//
+// +genstrument:wrap
+type ErrClasses interface {
+	// +genstrument:errclass Missing
+	Missing(ctx context.Context) error
+	// +genstrument:errclass ClassifyString
+	String(ctx context.Context) error
+	// +genstrument:errclass NotAFunc
+	Var(ctx context.Context) error
+	// +genstrument:errclass ErrClasses
+	Type(ctx context.Context) error
+	// +genstrument:errclass ClassifyString
+	NoError(ctx context.Context) int
+}
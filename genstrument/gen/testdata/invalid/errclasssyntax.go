@@ -0,0 +1,11 @@
+package invalid
+
+import "context"
+
+// ErrClassSyntax has an errclass directive with too many arguments.
+//
+// +genstrument:wrap
+// +genstrument:errclass ClassifyString ClassifyString
+type ErrClassSyntax interface {
+	Get(ctx context.Context) error
+}
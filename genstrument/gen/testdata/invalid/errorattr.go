@@ -0,0 +1,14 @@
+package invalid
+
+import "context"
+
+// ErrorAttrs sets attributes from error results without a setter.
+//
+// +genstrument:wrap
+// +genstrument:attr error err
+type ErrorAttrs interface {
+	Get(ctx context.Context, key string) (value string, err error)
+	Put(ctx context.Context, key string) (err error)
+	// +genstrument:attr failure err on-error
+	Delete(ctx context.Context, key string) (err error)
+}
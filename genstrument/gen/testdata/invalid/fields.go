@@ -0,0 +1,27 @@
+package invalid
+
+import (
+	"context"
+	"net/http"
+)
+
+type Item struct {
+	Name   string
+	Header http.Header
+	Values []string
+}
+
+func (i *Item) Title() string { return i.Name }
+
+func (i *Item) Copy() Item { return *i }
+
+// Fields has invalid field paths.
+//
+// +genstrument:wrap
+type Fields interface {
+	// +genstrument:attr missing item.Missing
+	// +genstrument:attr header item.Header.Get
+	// +genstrument:attr name item.Name()
+	// +genstrument:attr title item.Copy().Title()
+	Get(ctx context.Context, item *Item) error
+}
@@ -0,0 +1,12 @@
+package invalid
+
+import "context"
+
+// FieldSyntax has attr directives which are not field paths.
+//
+// +genstrument:wrap
+type FieldSyntax interface {
+	// +genstrument:attr get item.Header.Get("X")
+	// +genstrument:attr index item.Values[0]
+	Get(ctx context.Context, item *Item) error
+}
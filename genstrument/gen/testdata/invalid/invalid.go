@@ -0,0 +1,12 @@
+package invalid
+
+import "context"
+
+// Service has invalid directives.
+//
+// +genstrument:wrap
+// +genstrument:bogus
+type Service interface {
+	// +genstrument:attr key
+	Get(ctx context.Context, key string) error
+}
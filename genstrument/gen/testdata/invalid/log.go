@@ -0,0 +1,12 @@
+package invalid
+
+import "context"
+
+// Logged has a log directive on a method.
+//
+// +genstrument:wrap
+// +genstrument:log
+type Logged interface {
+	// +genstrument:log
+	Get(ctx context.Context) error
+}
@@ -0,0 +1,20 @@
+package invalid
+
+import "context"
+
+// Unmetered does not record metrics.
+//
+// +genstrument:wrap
+type Unmetered interface {
+	// +genstrument:metrics
+	Get(ctx context.Context) error
+}
+
+// Metered selects an unknown metric attribute.
+//
+// +genstrument:wrap
+// +genstrument:metrics
+type Metered interface {
+	// +genstrument:metrics missing
+	Get(ctx context.Context) error
+}
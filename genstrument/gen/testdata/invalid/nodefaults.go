@@ -0,0 +1,2 @@
+// +genstrument:prefix Traced
+package invalid
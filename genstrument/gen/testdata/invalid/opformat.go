@@ -0,0 +1,19 @@
+package invalid
+
+import "context"
+
+// Unclosed has an invalid operation name template.
+//
+// +genstrument:wrap
+// +genstrument:opformat {{.Method
+type Unclosed interface {
+	Get(ctx context.Context) error
+}
+
+// Unknown names its spans with an unknown field.
+//
+// +genstrument:wrap
+// +genstrument:opformat {{.Receiver}}.{{.Method}}
+type Unknown interface {
+	Get(ctx context.Context) error
+}
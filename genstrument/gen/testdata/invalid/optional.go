@@ -0,0 +1,17 @@
+package invalid
+
+import (
+	"context"
+	"io"
+)
+
+// Conflicting has optional interfaces adding no methods, or conflicting with its methods.
+//
+// +genstrument:wrap
+// +genstrument:optional io.Closer
+// +genstrument:optional io.ReadCloser fmt.Stringer
+// +genstrument:optional context.Background Missing
+type Conflicting interface {
+	Close() error
+	Read(ctx context.Context) error
+}
@@ -0,0 +1,17 @@
+package invalid
+
+import "context"
+
+// Pinger is an optional interface with directives on its method.
+type Pinger interface {
+	// +genstrument:log
+	Ping(ctx context.Context, target string) error
+}
+
+// Pinged keeps the Pinger implementation of the wrapped value.
+//
+// +genstrument:wrap
+// +genstrument:optional Pinger
+type Pinged interface {
+	Get(ctx context.Context) error
+}
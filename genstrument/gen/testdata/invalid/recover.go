@@ -0,0 +1,11 @@
+package invalid
+
+import "context"
+
+// Recovering recovers a method without an error result.
+//
+// +genstrument:wrap
+type Recovering interface {
+	// +genstrument:recover
+	Get(ctx context.Context) string
+}
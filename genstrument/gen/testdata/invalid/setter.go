@@ -0,0 +1,31 @@
+package invalid
+
+import (
+	"context"
+
+	"github.com/justenwalker/genstrument"
+)
+
+type ID string
+
+type Point struct{ X, Y int }
+
+func SetPoint(p Point, attr genstrument.AttributeSetter) {}
+
+func SetStringish[S ~string](s S, attr genstrument.AttributeSetter) {}
+
+func NoSetter(p Point) {}
+
+// Setters registers invalid setters.
+//
+// +genstrument:wrap
+// +genstrument:setter ID SetStringish
+// +genstrument:setter Point NoSetter
+// +genstrument:setter Point SetStringish
+// +genstrument:setter ID SetPoint
+// +genstrument:setter Missing SetPoint
+// +genstrument:setter SetPoint SetPoint
+type Setters interface {
+	// +genstrument:attr id id
+	Get(ctx context.Context, id ID) error
+}
@@ -0,0 +1,69 @@
+// Code generated by Genstrument. DO NOT EDIT.
+
+package stale
+
+import (
+	"context"
+	"github.com/justenwalker/genstrument"
+	"runtime/debug"
+)
+
+// InstrumentStore adds APM traces around the wrapped Store using the provided tracer.
+func InstrumentStore(tracer genstrument.Tracer, wrapped Store, opts ...genstrument.WrapperOption) Store {
+	// Return wrapped as-is if it is already instrumented the same way, and there are no options
+	if iw, ok := wrapped.(interface{ genstrumentWrapper() *instrumentedStore }); ok && len(opts) == 0 {
+		if prev := iw.genstrumentWrapper(); genstrument.SameInstrument(prev.tracer, tracer) {
+			return wrapped
+		}
+	}
+	return &instrumentedStore{
+		tracer:  tracer,
+		cfg:     genstrument.NewWrapperConfig(opts...),
+		wrapped: wrapped,
+	}
+}
+
+type instrumentedStore struct {
+	wrapped Store
+	tracer  genstrument.Tracer
+	cfg     *genstrument.WrapperConfig
+}
+
+// GenstrumentUnwrap returns the wrapped Store.
+func (w *instrumentedStore) GenstrumentUnwrap() interface{} {
+	return w.wrapped
+}
+
+func (w *instrumentedStore) genstrumentWrapper() *instrumentedStore {
+	return w
+}
+
+func (w *instrumentedStore) Get(ctx context.Context, key string) (ret0 []byte, err error) {
+	if w.cfg.Disabled("Get") {
+		return w.wrapped.Get(ctx, key)
+	}
+	// Start Span
+	var span genstrument.Span
+	ctx, span = w.tracer.StartSpan(ctx, w.cfg.OperationName("stale.Store:Get"))
+	w.cfg.SetAttributes(span)
+	// Finish on Panic
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &genstrument.PanicError{Value: r, Stack: debug.Stack()}
+			genstrument.EndPanic(span, r, panicErr.Stack)
+			panic(r)
+		}
+	}()
+
+	// call Wrapped Function
+	ret0, err = w.wrapped.Get(ctx, key)
+	// Finish Span with Error
+	if err != nil {
+		span.EndError(err)
+		return
+	}
+
+	// Finish Span with Success
+	span.EndSuccess(ctx)
+	return
+}
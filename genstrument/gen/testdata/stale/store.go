@@ -0,0 +1,12 @@
+// Package stale has a generated file from before Delete was added to Store, which no longer type-checks.
+package stale
+
+import "context"
+
+// Store
+//
+// +genstrument:wrap
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
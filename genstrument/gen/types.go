@@ -0,0 +1,564 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+type parsedFile struct {
+	Filename   string
+	Package    string
+	PkgPath    string
+	Scope      *types.Scope
+	Interfaces []wrappedInterface
+	Functions  []wrappedFunction
+}
+
+type functionConfig struct {
+	OperationName      string
+	Prefix             string
+	ExternalType       *ast.SelectorExpr
+	AttributeFunctions map[string]*attributeKeyFunc
+	Metrics            *metricsConfig
+	Log                *logConfig
+	Recover            bool
+	// Setters are the setter directives inherited from the interface and defaults.
+	Setters []*setterConfig
+	// Consts and ContextAttrs are the const and ctxattr directives, including those inherited from the interface.
+	Consts       []constAttr
+	ContextAttrs []*ctxAttr
+	// ErrClass is the errclass directive, or the one inherited from the interface or defaults.
+	ErrClass *errClass
+}
+
+// logConfig is a log directive.
+type logConfig struct {
+	// NoTrace generates logging wrappers without tracing.
+	NoTrace bool
+	Pos     token.Pos
+}
+
+// metricsConfig is a metrics directive.
+type metricsConfig struct {
+	// NoTrace generates metrics-only wrappers.
+	NoTrace bool
+	// Keys are the attribute keys also recorded as metric attributes.
+	Keys []string
+	Pos  token.Pos
+}
+
+type attributeKeyFunc struct {
+	Key  string
+	Func ast.Expr
+	// Arg is the argument or named result the attribute is set from.
+	Arg string
+	// Path are the fields and methods selected from Arg, like User and ID for req.User.ID,
+	// or empty if the attribute is set from the whole argument.
+	Path []attrSelector
+	// Mode is when the attribute is set, or empty for the default of arguments or results.
+	Mode string
+	Pos  token.Pos
+	// Scope is the scope of the file declaring the directive, used to resolve Func.
+	Scope *types.Scope
+}
+
+// The modes of attributes: always set, or only set when the call succeeds or fails.
+// Arguments are always set by default, and results when the call succeeds.
+const (
+	attrAlways    = "always"
+	attrOnSuccess = "on-success"
+	attrOnError   = "on-error"
+)
+
+// attrSelector is a field, or a method called without arguments, in the path of an attr directive.
+type attrSelector struct {
+	Name string
+	Call bool
+}
+
+// constAttr is a const directive, setting a static attribute.
+type constAttr struct {
+	Key string
+	// Method is the genstrument.AttributeSetter method setting the value, like String or Int64,
+	// and Value the Go literal of the value.
+	Method string
+	Value  string
+}
+
+// ctxAttr is a ctxattr directive, setting an attribute from the value the function Func extracts from the context.
+type ctxAttr struct {
+	Key  string
+	Func ast.Expr
+	// Setter is the setter function of the value, or nil to find one.
+	Setter ast.Expr
+	Pos    token.Pos
+	// Scope is the scope of the file declaring the directive, used to resolve Func and Setter.
+	Scope *types.Scope
+}
+
+// errClass is an errclass directive, classifying the errors of calls with the function Func.
+type errClass struct {
+	Func ast.Expr
+	Pos  token.Pos
+	// Scope is the scope of the file declaring the directive, used to resolve Func.
+	Scope *types.Scope
+}
+
+// mergeByKey returns the attributes of base not overridden by one of over with the same key, followed by over.
+func mergeByKey[T any](base, over []T, key func(T) string) []T {
+	if len(over) == 0 {
+		return base
+	}
+	merged := make([]T, 0, len(base)+len(over))
+	for _, b := range base {
+		if !slices.ContainsFunc(over, func(o T) bool { return key(o) == key(b) }) {
+			merged = append(merged, b)
+		}
+	}
+	return append(merged, over...)
+}
+
+// setterConfig is a setter directive, registering the setter function Func for the type Type.
+type setterConfig struct {
+	Type ast.Expr
+	Func ast.Expr
+	Pos  token.Pos
+	// Scope is the scope of the file declaring the directive, used to resolve Type and Func.
+	Scope *types.Scope
+}
+
+type wrappedFunction struct {
+	Name       *ast.Ident
+	Object     *types.Func
+	TypeParams []typeParam
+	Arguments  []funcArg
+	Returns    []funcArg
+	Config     functionConfig
+}
+
+type interfaceConfig struct {
+	Prefix            string
+	ExternalType      *ast.SelectorExpr
+	ConstructorPrefix string
+	Metrics           *metricsConfig
+	Log               *logConfig
+	Recover           bool
+	Optional          []optionalConfig
+	// OperationFormat is the operation name template of the interface or, if it has none, of its defaults.
+	OperationFormat *template.Template
+	// AttributeFunctions are the attr directives of the interface and its defaults, inherited by its methods.
+	AttributeFunctions map[string]*attributeKeyFunc
+	// Setters are the setter directives of the interface followed by those of its defaults.
+	Setters []*setterConfig
+	// Consts and ContextAttrs are the const and ctxattr directives of the interface, inherited by its methods.
+	Consts       []constAttr
+	ContextAttrs []*ctxAttr
+	// ErrClass is the errclass directive of the interface or its defaults, inherited by its methods.
+	ErrClass *errClass
+}
+
+// fileConfig holds the directives following a defaults directive, inherited by the wrapped declarations
+// of a file or, in doc.go, of a package.
+type fileConfig struct {
+	// Prefix is the prefix of wrapper types, and FunctionPrefix the prefix of package-level function wrappers.
+	Prefix             string
+	FunctionPrefix     string
+	ConstructorPrefix  string
+	OperationFormat    *template.Template
+	AttributeFunctions map[string]*attributeKeyFunc
+	Metrics            *metricsConfig
+	Log                *logConfig
+	Setters            []*setterConfig
+	ErrClass           *errClass
+}
+
+// merge returns the defaults d overridden by the directives of o.
+func (d fileConfig) merge(o fileConfig) fileConfig {
+	if o.Prefix != "" {
+		d.Prefix = o.Prefix
+	}
+	if o.FunctionPrefix != "" {
+		d.FunctionPrefix = o.FunctionPrefix
+	}
+	if o.ConstructorPrefix != "" {
+		d.ConstructorPrefix = o.ConstructorPrefix
+	}
+	if o.OperationFormat != nil {
+		d.OperationFormat = o.OperationFormat
+	}
+	if o.Metrics != nil {
+		d.Metrics = o.Metrics
+	}
+	if o.Log != nil {
+		d.Log = o.Log
+	}
+	if o.ErrClass != nil {
+		d.ErrClass = o.ErrClass
+	}
+	d.AttributeFunctions = mergeAttrs(d.AttributeFunctions, o.AttributeFunctions)
+	d.Setters = appendSetters(o.Setters, d.Setters)
+	return d
+}
+
+// applyInterface sets the defaults d on the directives missing from cfg.
+func (d fileConfig) applyInterface(cfg *interfaceConfig) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = d.Prefix
+	}
+	if cfg.ConstructorPrefix == "" {
+		cfg.ConstructorPrefix = d.ConstructorPrefix
+	}
+	if cfg.OperationFormat == nil {
+		cfg.OperationFormat = d.OperationFormat
+	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = d.Metrics
+	}
+	if cfg.Log == nil {
+		cfg.Log = d.Log
+	}
+	if cfg.ErrClass == nil {
+		cfg.ErrClass = d.ErrClass
+	}
+	cfg.AttributeFunctions = mergeAttrs(d.AttributeFunctions, cfg.AttributeFunctions)
+	cfg.Setters = appendSetters(cfg.Setters, d.Setters)
+}
+
+// applyFunction sets the defaults d on the directives missing from the package-level function cfg.
+func (d fileConfig) applyFunction(cfg *functionConfig) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = d.FunctionPrefix
+	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = d.Metrics
+	}
+	if cfg.Log == nil {
+		cfg.Log = d.Log
+	}
+	if cfg.ErrClass == nil {
+		cfg.ErrClass = d.ErrClass
+	}
+	cfg.AttributeFunctions = mergeAttrs(d.AttributeFunctions, cfg.AttributeFunctions)
+	cfg.Setters = appendSetters(cfg.Setters, d.Setters)
+}
+
+// appendSetters returns the setter directives of first followed by those of then, which they take precedence over.
+func appendSetters(first, then []*setterConfig) []*setterConfig {
+	if len(then) == 0 {
+		return first
+	}
+	return append(first[:len(first):len(first)], then...)
+}
+
+// mergeAttrs returns the attr directives of base overridden by those of over, by argument name.
+func mergeAttrs(base, over map[string]*attributeKeyFunc) map[string]*attributeKeyFunc {
+	if len(base) == 0 {
+		return over
+	}
+	merged := make(map[string]*attributeKeyFunc, len(base)+len(over))
+	for arg, attr := range base {
+		merged[arg] = attr
+	}
+	for arg, attr := range over {
+		merged[arg] = attr
+	}
+	return merged
+}
+
+// optionalConfig is an interface named by an optional directive.
+type optionalConfig struct {
+	Type ast.Expr
+	Pos  token.Pos
+}
+
+type wrappedInterface struct {
+	Name       *ast.Ident
+	Object     *types.TypeName
+	TypeParams []typeParam
+	Config     interfaceConfig
+	Functions  []wrappedFunction
+}
+
+type typeParam struct {
+	Name       string
+	Constraint types.Type
+}
+
+type funcArg struct {
+	Name     string
+	Type     types.Type
+	Pos      token.Pos
+	Variadic bool
+}
+
+// TemplateDataVersion is the version of the TemplateData contract passed to templates.
+// Fields may be added to the template data types within a version; it is incremented
+// when a field is removed, renamed or changes meaning.
+const TemplateDataVersion = 4
+
+// OperationNameData is the data of an operation name template, set by Options.OperationFormat
+// or an opformat directive.
+type OperationNameData struct {
+	// PkgPath and Package are the import path and name of the package declaring the interface or function.
+	PkgPath string
+	Package string
+	// Interface is the name of the interface declaring the method, or empty for a function.
+	Interface string
+	// Method is the name of the method or function.
+	Method string
+	// TypeParams are the type parameter names of a generic interface.
+	TypeParams []string
+}
+
+// TemplateImport is an import of the generated file.
+type TemplateImport struct {
+	// Name is the name the package is referred to by in the generated code.
+	Name string
+	// Package is the declared name of the package.
+	// The import needs an explicit name when it differs from Name.
+	Package string
+	// PkgPath is the import path.
+	PkgPath string
+}
+
+// TemplateData is the data the templates are executed with to render one output file.
+type TemplateData struct {
+	// Version is TemplateDataVersion.
+	Version int
+	// Package is the name of the output package.
+	Package string
+	// Header holds the lines of the header comment, without the leading //.
+	Header []string
+	// BuildTags is the build constraint expression for the //go:build line, if any.
+	BuildTags string
+	// Imports are the packages used by the generated code, sorted by path.
+	Imports []TemplateImport
+	// Functions are the wrapped package-level functions.
+	Functions []TemplateFunctionConfig
+	// Types are the wrapped interfaces.
+	Types []TemplateTypeConfig
+}
+
+// TemplateFunctionConfig describes a wrapped function or interface method.
+// Type names and expressions are already qualified for the output package.
+type TemplateFunctionConfig struct {
+	// Name is the name of the function or method.
+	Name string
+	// WrapperName is the name of the generated wrapper of a package-level function.
+	WrapperName string
+	// QualifiedName is the name used to call a package-level function from the output package.
+	QualifiedName string
+	// OperationName is the name of the span.
+	OperationName string
+	// TypeParamSpec and TypeParamNames are the type parameter list of a generic function,
+	// like [T any] and [T], or empty.
+	TypeParamSpec  string
+	TypeParamNames string
+	// TracerArg is the name of the tracer parameter of a function wrapper.
+	TracerArg string
+	// ContextArg is the name of the context variable passed to StartSpan.
+	ContextArg string
+	// ContextVar declares ContextArg when the function has no context argument.
+	ContextVar string
+	// ErrorReturn is the name of the error result, if any.
+	ErrorReturn string
+	// ArgHasAttributes and ReturnHasAttributes report whether any argument or result has an AttrKey.
+	ArgHasAttributes    bool
+	ReturnHasAttributes bool
+	// Arguments and Returns are the parameters and results, with names assigned to unnamed ones.
+	Arguments []TemplateFunctionArg
+	Returns   []TemplateFunctionArg
+	// NoTrace is true when the wrapper does not trace, only recording metrics or logs.
+	NoTrace bool
+	// Metrics is true when the wrapper records RED metrics with a genstrument.Meter.
+	Metrics bool
+	// MeterArg is the name of the meter parameter of a function wrapper that records metrics.
+	MeterArg string
+	// MeasurementVar is the name of the genstrument.Measurement variable of a wrapper that records metrics.
+	MeasurementVar string
+	// Log is true when the wrapper logs calls to a *slog.Logger.
+	Log bool
+	// LoggerArg is the name of the logger parameter of a function wrapper that logs calls.
+	LoggerArg string
+	// LoggerType is the qualified *slog.Logger type of a wrapper that logs calls.
+	LoggerType string
+	// LogCallVar is the name of the *slogtracer.Call variable of a wrapper that logs calls.
+	LogCallVar string
+	// NewLogCall is the qualified slogtracer.NewCall function.
+	NewLogCall string
+	// Recover is true when a panic of the wrapped function is returned as a *genstrument.PanicError
+	// in ErrorReturn instead of being re-panicked.
+	Recover bool
+	// ConstAttributes are the static attributes of const directives.
+	ConstAttributes []TemplateConstAttr
+	// ContextAttributes are the attributes of ctxattr directives, extracted from ContextArg once the span is started.
+	ContextAttributes []TemplateContextAttr
+	// ErrorClassifier is the func(error) genstrument.ErrorClass of an errclass directive classifying
+	// a non-nil ErrorReturn, or empty.
+	ErrorClassifier string
+	// ErrorClassVar is the name of the genstrument.ErrorClass variable set by ErrorClassifier.
+	ErrorClassVar string
+}
+
+// TemplateConstAttr is a static attribute.
+type TemplateConstAttr struct {
+	// AttrKey is the attribute key.
+	AttrKey string
+	// Method is the genstrument.AttributeSetter method setting Value, like String or Int64.
+	Method string
+	// Value is the Go literal of the value.
+	Value string
+	// MetricAttr is true when the attribute is also recorded on the metrics.
+	MetricAttr bool
+}
+
+// TemplateContextAttr is an attribute extracted from the context.
+type TemplateContextAttr struct {
+	// AttrKey is the attribute key.
+	AttrKey string
+	// Func is the function extracting the value from the context.
+	Func string
+	// HasOK is true when Func also returns a bool reporting whether the context has a value.
+	HasOK bool
+	// AttrFunc is the setter called with the value and the attribute.
+	AttrFunc string
+	// MetricAttr is true when the attribute is also recorded on the metrics.
+	MetricAttr bool
+}
+
+// TemplateFunctionArg is a parameter or result of a wrapped function.
+type TemplateFunctionArg struct {
+	// Name is the parameter name.
+	Name string
+	// Type is the parameter type. The type of a variadic parameter starts with "...".
+	Type string
+	// Variadic is true for the final ...T parameter.
+	Variadic bool
+	// AttrFunc is the setter called with the value and the span attribute, if it is recorded.
+	AttrFunc string
+	// AttrKey is the span attribute key, or empty if it is not recorded.
+	// It is empty for the error result unless its attr directive names a setter,
+	// since the error is already recorded when the span ends.
+	AttrKey string
+	// MetricAttr is true when the attribute is also recorded on the metrics.
+	MetricAttr bool
+	// AttrMode is when the attribute is set: "always", "on-success" or "on-error".
+	AttrMode string
+	// Fields are the attributes set from field paths of the value, like req.User.ID.
+	Fields []TemplateFieldAttr
+}
+
+// TemplateFieldAttr is an attribute set from a field path of a parameter or result.
+type TemplateFieldAttr struct {
+	// AttrFunc is the setter called with the value and the span attribute.
+	AttrFunc string
+	// AttrKey is the span attribute key.
+	AttrKey string
+	// Value is the selector expression of the value, like req.User.ID, or v.ID when the guards bind v.
+	Value string
+	// Guards are the conditions of the nested if statements around the setter call, checking the pointers
+	// and interfaces along Value are not nil. A guard checking the result of a method call binds it first,
+	// so that it is only called once, like v := req.GetUser(); v != nil for req.GetUser().ID.
+	// Guards is empty if there are none.
+	Guards []string
+	// MetricAttr is true when the attribute is also recorded on the metrics.
+	MetricAttr bool
+	// AttrMode is when the attribute is set: "always", "on-success" or "on-error".
+	AttrMode string
+
+	// selectors are the fields and method calls of Value after the argument,
+	// and nilChecks the number of selectors after which the value is checked for nil.
+	selectors []string
+	nilChecks []int
+}
+
+// resolve sets Value and Guards for the argument named arg.
+func (fa *TemplateFieldAttr) resolve(arg string) {
+	// The variable binding a method result, which must not shadow the package of the setter.
+	v := "v"
+	for strings.Split(fa.AttrFunc, ".")[0] == v {
+		v += "v"
+	}
+	base, start := arg, 0
+	expr := func(n int) string {
+		return strings.Join(append([]string{base}, fa.selectors[start:n]...), ".")
+	}
+	fa.Guards = nil
+	var checks []string
+	for _, n := range fa.nilChecks {
+		if !slices.ContainsFunc(fa.selectors[start:n], isCallSelector) {
+			checks = append(checks, expr(n)+" != nil")
+			continue
+		}
+		if len(checks) > 0 {
+			fa.Guards = append(fa.Guards, strings.Join(checks, " && "))
+			checks = nil
+		}
+		fa.Guards = append(fa.Guards, fmt.Sprintf("%s := %s; %s != nil", v, expr(n), v))
+		base, start = v, n
+	}
+	if len(checks) > 0 {
+		fa.Guards = append(fa.Guards, strings.Join(checks, " && "))
+	}
+	fa.Value = expr(len(fa.selectors))
+}
+
+// isCallSelector reports whether the selector sel of a field path is a method call.
+func isCallSelector(sel string) bool {
+	return strings.HasSuffix(sel, "()")
+}
+
+// TemplateTypeConfig describes a wrapped interface.
+type TemplateTypeConfig struct {
+	// Name is the name of the interface declaration.
+	Name string
+	// ExternalType is the qualified external type the interface mirrors, if any.
+	ExternalType string
+	// TypeName is the name of the generated wrapper type.
+	TypeName string
+	// ConstructorName is the name of the generated constructor.
+	ConstructorName string
+	// QualifiedName is the wrapped type as referred to from the output package.
+	QualifiedName string
+	// TypeParamSpec and TypeParamNames are the type parameter list of a generic interface,
+	// like [K comparable, V any] and [K, V], or empty.
+	TypeParamSpec  string
+	TypeParamNames string
+	// Functions are the methods of the interface.
+	Functions []TemplateFunctionConfig
+	// NoTrace is true when the wrapper type does not trace, only recording metrics or logs.
+	NoTrace bool
+	// Metrics is true when the wrapper type records RED metrics with a genstrument.Meter.
+	Metrics bool
+	// Log is true when the wrapper type logs calls to a *slog.Logger.
+	Log bool
+	// LoggerType is the qualified *slog.Logger type of a wrapper type that logs calls.
+	LoggerType string
+	// Optional are the optional interfaces of the wrapper type.
+	Optional []TemplateOptionalType
+}
+
+// TemplateOptionalType is an optional interface of a wrapped interface.
+// The constructor returns a wrapper implementing it when the wrapped value does.
+// TypeName is the type adding its methods to the wrapper, and Functions are the methods
+// not already in the wrapped interface.
+type TemplateOptionalType struct {
+	TemplateTypeConfig
+	// Var is the name of the constructor variable holding the wrapped value asserted to the interface,
+	// and OKVar is the name of the variable reporting whether the assertion succeeded.
+	Var   string
+	OKVar string
+}
+
+// TemplateMethodData is the data of the "method" template, built with the method_data template function,
+// and of the "start" and "finish" templates, built with function_data for a package-level function,
+// which leaves Type empty.
+type TemplateMethodData struct {
+	Type     TemplateTypeConfig
+	Function TemplateFunctionConfig
+}
@@ -3,13 +3,13 @@ module github.com/justenwalker/genstrument/genstrument
 go 1.23.0
 
 require (
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/sebdah/goldie/v2 v2.5.5
 	golang.org/x/mod v0.21.0
 	golang.org/x/tools v0.25.0
 )
 
 require (
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sergi/go-diff v1.3.1 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 )
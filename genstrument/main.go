@@ -3,29 +3,103 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+
+	"github.com/justenwalker/genstrument/genstrument/gen"
 )
 
+// stringList is a flag.Value collecting every occurrence of a repeated flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
 func main() {
 	ctx := context.Background()
 	var (
-		inFile  string
-		outFile string
+		cfg         gen.Config
+		pkgPatterns stringList
+		templates   stringList
+		check       bool
 	)
-	flag.StringVar(&inFile, "input", "", "Input File to parse")
-	flag.StringVar(&outFile, "output", "", "Output file to write generated code.")
+	flag.StringVar(&cfg.InputFile, "input", "", "Input File to parse")
+	flag.StringVar(&cfg.OutputFile, "output", "", "Output file to write generated code.")
+	flag.Var(&pkgPatterns, "package", "Package pattern to scan for directives, like ./... (may be repeated)")
+	flag.StringVar(&cfg.Options.OutputPattern, "output-pattern", "genstrument.gen.go", "Output file name in each package when using -package. Use {{base}} to write one file per source file, like {{base}}_genstrument.go")
+	flag.StringVar(&cfg.Options.Package, "output-package", "", "Name of the generated package. Defaults to the source package or the output directory name.")
+	flag.StringVar(&cfg.Options.ConstructorPrefix, "constructor-prefix", "Instrument", "Default prefix of generated constructors")
+	flag.StringVar(&cfg.Options.TypePrefix, "type-prefix", "instrumented", "Default prefix of generated wrapper types")
+	flag.StringVar(&cfg.Options.FunctionPrefix, "func-prefix", "Trace", "Default prefix of generated wrapper functions")
+	flag.StringVar(&cfg.Options.Header, "header", "", "Comment text added to the top of generated files")
+	flag.StringVar(&cfg.Options.BuildTags, "tags", "", "Build constraint added to generated files as a //go:build line")
+	flag.BoolVar(&cfg.Options.Metrics, "metrics", false, "Record RED metrics with a genstrument.Meter in every wrapper without a metrics directive")
+	flag.BoolVar(&cfg.Options.Log, "log", false, "Log calls to a *slog.Logger in every wrapper without a log directive")
+	flag.StringVar(&cfg.Options.OperationFormat, "opformat", "", "Default template of operation names, like {{.PkgPath}}/{{.Interface}}.{{.Method}}")
+	flag.Var(&templates, "template", "Custom template file parsed on top of the built-in templates (may be repeated)")
+	flag.BoolVar(&check, "check", false, "Check that the output files are up-to-date without writing them. Prints a diff and exits non-zero if they are not.")
 	flag.Parse()
-	if inFile == "" || outFile == "" {
-		log.Println("Must provide an -input and -output flag")
+	cfg.Packages = pkgPatterns
+	cfg.Options.TemplateFiles = templates
+	cfg.Options.Check = check
+	if len(cfg.Packages) > 0 {
+		if cfg.InputFile != "" || cfg.OutputFile != "" {
+			log.Println("The -package flag cannot be combined with -input and -output")
+			flag.Usage()
+			os.Exit(1)
+		}
+		results, err := gen.GeneratePackages(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Generate failed: %v", err)
+		}
+		output(results, check)
+		return
+	}
+	if cfg.InputFile == "" || cfg.OutputFile == "" {
+		log.Println("Must provide an -input and -output flag, or a -package flag")
 		flag.Usage()
 		os.Exit(1)
 	}
-	res, err := Generate(ctx, inFile, outFile, &Options{})
+	res, err := gen.Generate(ctx, cfg)
 	if err != nil {
 		log.Fatalf("Generate failed: %v", err)
 	}
-	if err = res.WriteOutput(); err != nil {
-		log.Fatalf("write '%s' failed: %v", res.OutputFile, err)
+	output([]*gen.Result{res}, check)
+}
+
+// output writes the results, or in check mode, prints the diff of every stale output file
+// and exits with status 1 if there are any.
+func output(results []*gen.Result, check bool) {
+	if !check {
+		for _, res := range results {
+			if err := res.WriteOutput(); err != nil {
+				log.Fatalf("write '%s' failed: %v", res.OutputFile, err)
+			}
+		}
+		return
+	}
+	stale := 0
+	for _, res := range results {
+		diff, err := res.Diff()
+		if err != nil {
+			log.Fatalf("check '%s' failed: %v", res.OutputFile, err)
+		}
+		if diff == "" {
+			continue
+		}
+		stale++
+		fmt.Print(diff)
+	}
+	if stale > 0 {
+		log.Printf("%d generated file(s) are out of date; run go generate", stale)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,169 @@
+package genstrumenttest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// SpanAssertion checks a recorded span, failing the test immediately when a check fails.
+// Its methods return the SpanAssertion so checks can be chained.
+type SpanAssertion struct {
+	t    testing.TB
+	span *Span
+}
+
+// RequireSpan returns an assertion on the first span recorded with the operation name,
+// failing the test if there is none.
+func (t *Tracer) RequireSpan(tb testing.TB, operationName string) *SpanAssertion {
+	tb.Helper()
+	s := t.Find(operationName)
+	if s == nil {
+		tb.Fatalf("no span %q was recorded; recorded spans: %v", operationName, t.Spans())
+	}
+	return &SpanAssertion{t: tb, span: s}
+}
+
+// Span returns the checked span.
+func (a *SpanAssertion) Span() *Span {
+	return a.span
+}
+
+// HasAttr checks that the attribute key was set to want.
+// Integers and floats are compared as int64 and float64, and an error matches a string equal to its message.
+func (a *SpanAssertion) HasAttr(key string, want interface{}) *SpanAssertion {
+	a.t.Helper()
+	got, ok := a.span.Attr(key)
+	if !ok {
+		a.t.Fatalf("span %q: attribute %q was not set; attributes: %v", a.span.name, key, a.span.Attrs())
+	}
+	if !attrEqual(got, want) {
+		a.t.Fatalf("span %q: attribute %q is %#v, want %#v", a.span.name, key, got, want)
+	}
+	return a
+}
+
+// NoAttr checks that the attribute key was not set.
+func (a *SpanAssertion) NoAttr(key string) *SpanAssertion {
+	a.t.Helper()
+	if got, ok := a.span.Attr(key); ok {
+		a.t.Fatalf("span %q: attribute %q is %#v, want it unset", a.span.name, key, got)
+	}
+	return a
+}
+
+// HasParent checks that the span was started from the context of a span with the operation name.
+func (a *SpanAssertion) HasParent(operationName string) *SpanAssertion {
+	a.t.Helper()
+	if p := a.span.Parent(); p == nil || p.name != operationName {
+		a.t.Fatalf("span %q: parent is %v, want %q", a.span.name, p, operationName)
+	}
+	return a
+}
+
+// IsRoot checks that the span has no parent.
+func (a *SpanAssertion) IsRoot() *SpanAssertion {
+	a.t.Helper()
+	if p := a.span.Parent(); p != nil {
+		a.t.Fatalf("span %q: parent is %q, want none", a.span.name, p.name)
+	}
+	return a
+}
+
+// EndedWithSuccess checks that the span ended with EndSuccess.
+func (a *SpanAssertion) EndedWithSuccess() *SpanAssertion {
+	a.t.Helper()
+	if !a.span.Ended() {
+		a.t.Fatalf("span %q has not ended", a.span.name)
+	}
+	if err := a.span.Err(); err != nil {
+		a.t.Fatalf("span %q ended with error %v, want success", a.span.name, err)
+	}
+	return a
+}
+
+// EndedWithError checks that the span ended with EndError.
+func (a *SpanAssertion) EndedWithError() *SpanAssertion {
+	a.t.Helper()
+	if !a.span.Ended() {
+		a.t.Fatalf("span %q has not ended", a.span.name)
+	}
+	if a.span.Err() == nil {
+		a.t.Fatalf("span %q ended with success, want an error", a.span.name)
+	}
+	if a.span.Canceled() {
+		a.t.Fatalf("span %q was canceled with %v, want an error", a.span.name, a.span.Err())
+	}
+	return a
+}
+
+// EndedWithErrorIs checks that the span ended with an error matching target, as reported by errors.Is.
+func (a *SpanAssertion) EndedWithErrorIs(target error) *SpanAssertion {
+	a.t.Helper()
+	a.EndedWithError()
+	if err := a.span.Err(); !errors.Is(err, target) {
+		a.t.Fatalf("span %q ended with error %v, want %v", a.span.name, err, target)
+	}
+	return a
+}
+
+// EndedWithPanic checks that the span ended with EndPanic.
+func (a *SpanAssertion) EndedWithPanic() *SpanAssertion {
+	a.t.Helper()
+	if !a.span.Ended() {
+		a.t.Fatalf("span %q has not ended", a.span.name)
+	}
+	if !a.span.Panicked() {
+		a.t.Fatalf("span %q ended with error %v, want a panic", a.span.name, a.span.Err())
+	}
+	return a
+}
+
+// EndedWithCancel checks that the span ended with EndCanceled.
+func (a *SpanAssertion) EndedWithCancel() *SpanAssertion {
+	a.t.Helper()
+	if !a.span.Ended() {
+		a.t.Fatalf("span %q has not ended", a.span.name)
+	}
+	if !a.span.Canceled() {
+		a.t.Fatalf("span %q ended with error %v, want a cancellation", a.span.name, a.span.Err())
+	}
+	return a
+}
+
+// attrEqual compares a recorded attribute value with an expected value.
+func attrEqual(got, want interface{}) bool {
+	if err, ok := got.(error); ok {
+		switch w := want.(type) {
+		case string:
+			return err.Error() == w
+		case error:
+			return errors.Is(err, w)
+		}
+		return false
+	}
+	want = normalize(want)
+	return reflect.DeepEqual(got, want)
+}
+
+// normalize converts basic values to the types recorded by the AttributeSetter methods.
+func normalize(v interface{}) interface{} {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return v
+}
@@ -0,0 +1,269 @@
+// Package genstrumenttest provides a recording genstrument.Tracer and assertions for testing instrumented code.
+package genstrumenttest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// Tracer is a genstrument.Tracer recording every span in memory. It is safe for concurrent use.
+// Spans started from the context of another span are recorded as its children.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// NewTracer returns an empty Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+type spanKey struct{}
+
+func (t *Tracer) StartSpan(ctx context.Context, operationName string) (context.Context, genstrument.Span) {
+	s := &Span{
+		tracer: t,
+		name:   operationName,
+		attrs:  make(map[string]interface{}),
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent.tracer == t {
+		s.parent = parent
+		parent.children = append(parent.children, s)
+	}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), &recordingSpan{span: s}
+}
+
+// Spans returns the recorded spans in the order they were started.
+func (t *Tracer) Spans() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Span(nil), t.spans...)
+}
+
+// Roots returns the recorded spans without a parent, in the order they were started.
+func (t *Tracer) Roots() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var roots []*Span
+	for _, s := range t.spans {
+		if s.parent == nil {
+			roots = append(roots, s)
+		}
+	}
+	return roots
+}
+
+// Find returns the first recorded span with the operation name, or nil.
+func (t *Tracer) Find(operationName string) *Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.name == operationName {
+			return s
+		}
+	}
+	return nil
+}
+
+// Reset forgets the recorded spans.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+// Span is a recorded span.
+//
+// Attribute values are recorded with the type of the AttributeSetter method they were set with:
+// string, int64, bool, float64, []string, []int64, []bool, []float64 or error.
+// Values set with Stringer are recorded as their string.
+// Attributes set with nested Attribute(key) calls are recorded with their keys joined by dots.
+type Span struct {
+	tracer   *Tracer
+	name     string
+	parent   *Span
+	children []*Span
+	attrs    map[string]interface{}
+	ended    bool
+	err      error
+	panicked bool
+	canceled bool
+}
+
+// Name returns the operation name.
+func (s *Span) Name() string {
+	return s.name
+}
+
+// Parent returns the parent span, or nil.
+func (s *Span) Parent() *Span {
+	return s.parent
+}
+
+// Children returns the spans started from the context of the span, in the order they were started.
+func (s *Span) Children() []*Span {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return append([]*Span(nil), s.children...)
+}
+
+// Attr returns the value of the attribute key, and whether it was set.
+func (s *Span) Attr(key string) (interface{}, bool) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	v, ok := s.attrs[key]
+	return v, ok
+}
+
+// Attrs returns a copy of the attributes.
+func (s *Span) Attrs() map[string]interface{} {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	attrs := make(map[string]interface{}, len(s.attrs))
+	for k, v := range s.attrs {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+// Ended reports whether the span has ended.
+func (s *Span) Ended() bool {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return s.ended
+}
+
+// Err returns the error the span ended with, or nil.
+func (s *Span) Err() error {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return s.err
+}
+
+// Panicked reports whether the span ended with EndPanic.
+// The panic is then recorded as a *genstrument.PanicError returned by Err.
+func (s *Span) Panicked() bool {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return s.panicked
+}
+
+// Canceled reports whether the span ended with EndCanceled.
+// The error of the cancellation is then returned by Err.
+func (s *Span) Canceled() bool {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return s.canceled
+}
+
+func (s *Span) String() string {
+	return s.name
+}
+
+type recordingSpan struct {
+	span *Span
+}
+
+func (r *recordingSpan) Attribute(key string) genstrument.AttributeSetter {
+	return &keyValue{key: key, span: r.span}
+}
+
+func (r *recordingSpan) EndSuccess(_ context.Context) {
+	r.end(nil, nil)
+}
+
+func (r *recordingSpan) EndError(err error) {
+	r.end(err, nil)
+}
+
+func (r *recordingSpan) EndPanic(v interface{}, stack []byte) {
+	r.end(&genstrument.PanicError{Value: v, Stack: stack}, func(s *Span) { s.panicked = true })
+}
+
+func (r *recordingSpan) EndCanceled(_ context.Context, err error) {
+	r.end(err, func(s *Span) { s.canceled = true })
+}
+
+// end ends the span with err, and marks it with mark, if any, in the same critical section,
+// so that readers never see an ended span which is not yet marked as panicked or canceled.
+func (r *recordingSpan) end(err error, mark func(*Span)) {
+	s := r.span
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.ended = true
+	s.err = err
+	if mark != nil {
+		mark(s)
+	}
+}
+
+type keyValue struct {
+	key  string
+	span *Span
+}
+
+func (k *keyValue) set(v interface{}) {
+	s := k.span
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.attrs[k.key] = v
+}
+
+func (k *keyValue) Attribute(key string) genstrument.AttributeSetter {
+	return &keyValue{
+		key:  strings.Join([]string{k.key, key}, "."),
+		span: k.span,
+	}
+}
+
+func (k *keyValue) Error(err error) {
+	k.set(err)
+}
+
+func (k *keyValue) Stringer(v fmt.Stringer) {
+	k.set(v.String())
+}
+
+func (k *keyValue) String(v string) {
+	k.set(v)
+}
+
+func (k *keyValue) Int64(v int64) {
+	k.set(v)
+}
+
+func (k *keyValue) Bool(v bool) {
+	k.set(v)
+}
+
+func (k *keyValue) Float64(v float64) {
+	k.set(v)
+}
+
+func (k *keyValue) StringSlice(v []string) {
+	k.set(append([]string(nil), v...))
+}
+
+func (k *keyValue) BoolSlice(v []bool) {
+	k.set(append([]bool(nil), v...))
+}
+
+func (k *keyValue) Float64Slice(v []float64) {
+	k.set(append([]float64(nil), v...))
+}
+
+func (k *keyValue) Int64Slice(v []int64) {
+	k.set(append([]int64(nil), v...))
+}
+
+var _ genstrument.Tracer = (*Tracer)(nil)
+var _ genstrument.PanicSpan = (*recordingSpan)(nil)
+var _ genstrument.CancelSpan = (*recordingSpan)(nil)
+var _ genstrument.AttributeSetter = (*keyValue)(nil)
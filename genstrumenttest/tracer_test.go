@@ -0,0 +1,102 @@
+package genstrumenttest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/justenwalker/genstrument"
+)
+
+func TestTracer(t *testing.T) {
+	tracer := NewTracer()
+	ctx, parent := tracer.StartSpan(context.Background(), "parent")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, child := tracer.StartSpan(ctx, "child")
+			genstrument.SetIntAttribute(i, child.Attribute("i"))
+			child.EndSuccess(ctx)
+		}(i)
+	}
+	wg.Wait()
+	errFailed := errors.New("failed")
+	genstrument.SetStringAttribute("v", parent.Attribute("str"))
+	parent.Attribute("nested").Attribute("ok").Bool(true)
+	parent.Attribute("list").StringSlice([]string{"a"})
+	genstrument.SetErrorAttribute(errFailed, parent.Attribute("err"))
+	parent.EndError(errFailed)
+
+	if n := len(tracer.Spans()); n != 11 {
+		t.Fatalf("expected 11 spans, got %d", n)
+	}
+	if roots := tracer.Roots(); len(roots) != 1 || roots[0].Name() != "parent" {
+		t.Fatalf("expected the parent span as the only root, got %v", roots)
+	}
+	if n := len(tracer.Find("parent").Children()); n != 10 {
+		t.Errorf("expected 10 children, got %d", n)
+	}
+	tracer.RequireSpan(t, "parent").
+		IsRoot().
+		HasAttr("str", "v").
+		HasAttr("nested.ok", true).
+		HasAttr("list", []string{"a"}).
+		HasAttr("err", "failed").
+		HasAttr("err", errFailed).
+		NoAttr("missing").
+		EndedWithError().
+		EndedWithErrorIs(errFailed)
+	tracer.RequireSpan(t, "child").
+		HasParent("parent").
+		EndedWithSuccess()
+	if v, _ := tracer.Find("child").Attr("i"); v == nil {
+		t.Errorf("expected child attribute i")
+	}
+}
+
+func TestTracerPanic(t *testing.T) {
+	tracer := NewTracer()
+	_, span := tracer.StartSpan(context.Background(), "op")
+	genstrument.EndPanic(span, "boom", nil)
+	tracer.RequireSpan(t, "op").EndedWithError().EndedWithPanic()
+	var panicErr *genstrument.PanicError
+	if err := tracer.Find("op").Err(); !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Errorf("expected a PanicError, got %v", err)
+	}
+}
+
+func TestAttrEqual(t *testing.T) {
+	tests := []struct {
+		got, want interface{}
+		equal     bool
+	}{
+		{got: int64(1), want: 1, equal: true},
+		{got: int64(1), want: uint8(1), equal: true},
+		{got: 1.5, want: float32(1.5), equal: true},
+		{got: "a", want: "a", equal: true},
+		{got: int64(1), want: "1", equal: false},
+		{got: []int64{1}, want: []int64{1}, equal: true},
+		{got: errors.New("x"), want: "x", equal: true},
+		{got: errors.New("x"), want: 1, equal: false},
+	}
+	for _, tt := range tests {
+		if eq := attrEqual(tt.got, tt.want); eq != tt.equal {
+			t.Errorf("attrEqual(%#v, %#v) = %v, want %v", tt.got, tt.want, eq, tt.equal)
+		}
+	}
+}
+
+func TestTracerEndClassified(t *testing.T) {
+	tracer := NewTracer()
+	errNotFound := errors.New("not found")
+	ctx := context.Background()
+	_, canceled := tracer.StartSpan(ctx, "canceled")
+	genstrument.EndClassified(ctx, canceled, context.Canceled, genstrument.ClassifyCanceled(context.Canceled))
+	_, expected := tracer.StartSpan(ctx, "expected")
+	genstrument.EndClassified(ctx, expected, errNotFound, genstrument.ExpectedErrors(errNotFound)(errNotFound))
+	tracer.RequireSpan(t, "canceled").EndedWithCancel()
+	tracer.RequireSpan(t, "expected").HasAttr(genstrument.ErrorAttribute, errNotFound).EndedWithSuccess()
+}
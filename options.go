@@ -0,0 +1,145 @@
+package genstrument
+
+import "fmt"
+
+// WrapperOption configures a wrapper created by a generated constructor.
+type WrapperOption func(*WrapperConfig)
+
+// WrapperConfig is the configuration of a wrapper, built from the options of its constructor.
+// A nil *WrapperConfig is the default configuration.
+type WrapperConfig struct {
+	prefix   string
+	attrs    []staticAttr
+	disabled map[string]bool
+}
+
+type staticAttr struct {
+	key   string
+	value any
+}
+
+// NewWrapperConfig returns the configuration built from opts, or nil if there are none.
+func NewWrapperConfig(opts ...WrapperOption) *WrapperConfig {
+	if len(opts) == 0 {
+		return nil
+	}
+	c := &WrapperConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithOperationPrefix prefixes the operation names of the spans, measurements and logged calls of the wrapper.
+func WithOperationPrefix(prefix string) WrapperOption {
+	return func(c *WrapperConfig) {
+		c.prefix += prefix
+	}
+}
+
+// WithAttribute sets the attribute key to value on every span, measurement and logged call of the wrapper,
+// like the instance of a service. Strings, integers, floats, booleans, errors, fmt.Stringers, and slices of
+// strings, signed integers, floats and booleans are set with the matching AttributeSetter method.
+// Unsigned integers are set with Int64, or as decimal strings when they overflow an int64.
+// Other values are set as strings formatted with fmt.Sprint.
+func WithAttribute(key string, value any) WrapperOption {
+	return func(c *WrapperConfig) {
+		c.attrs = append(c.attrs, staticAttr{key: key, value: value})
+	}
+}
+
+// WithoutMethods disables the instrumentation of the named methods: the wrapper calls them directly.
+func WithoutMethods(names ...string) WrapperOption {
+	return func(c *WrapperConfig) {
+		if c.disabled == nil {
+			c.disabled = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			c.disabled[name] = true
+		}
+	}
+}
+
+// OperationName returns the operation name with the prefix of the configuration.
+func (c *WrapperConfig) OperationName(name string) string {
+	if c == nil || c.prefix == "" {
+		return name
+	}
+	return c.prefix + name
+}
+
+// Disabled reports whether the instrumentation of the method is disabled.
+func (c *WrapperConfig) Disabled(method string) bool {
+	return c != nil && c.disabled[method]
+}
+
+// SetAttributes sets the attributes of the configuration on a span, measurement or logged call.
+func (c *WrapperConfig) SetAttributes(a interface {
+	Attribute(key string) AttributeSetter
+}) {
+	if c == nil {
+		return
+	}
+	for _, attr := range c.attrs {
+		setValue(attr.value, a.Attribute(attr.key))
+	}
+}
+
+func setValue(v any, setter AttributeSetter) {
+	switch v := v.(type) {
+	case string:
+		setter.String(v)
+	case int:
+		SetIntAttribute(v, setter)
+	case int8:
+		SetIntAttribute(v, setter)
+	case int16:
+		SetIntAttribute(v, setter)
+	case int32:
+		SetIntAttribute(v, setter)
+	case int64:
+		SetIntAttribute(v, setter)
+	case uint:
+		SetUintAttribute(v, setter)
+	case uint8:
+		SetUintAttribute(v, setter)
+	case uint16:
+		SetUintAttribute(v, setter)
+	case uint32:
+		SetUintAttribute(v, setter)
+	case uint64:
+		SetUintAttribute(v, setter)
+	case uintptr:
+		SetUintAttribute(v, setter)
+	case float32:
+		SetFloatAttribute(v, setter)
+	case float64:
+		setter.Float64(v)
+	case bool:
+		setter.Bool(v)
+	case error:
+		setter.Error(v)
+	case fmt.Stringer:
+		setter.Stringer(v)
+	case []string:
+		setter.StringSlice(v)
+	case []int:
+		SetIntSliceAttribute(v, setter)
+	case []int8:
+		SetIntSliceAttribute(v, setter)
+	case []int16:
+		SetIntSliceAttribute(v, setter)
+	case []int32:
+		SetIntSliceAttribute(v, setter)
+	case []int64:
+		setter.Int64Slice(v)
+	case []float32:
+		SetFloatSliceAttribute(v, setter)
+	case []float64:
+		setter.Float64Slice(v)
+	case []bool:
+		setter.BoolSlice(v)
+	default:
+		setter.String(fmt.Sprint(v))
+	}
+}
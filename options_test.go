@@ -0,0 +1,61 @@
+package genstrument_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/genstrumenttest"
+)
+
+func TestWithAttribute(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  interface{}
+	}{
+		{name: "string", value: "a", want: "a"},
+		{name: "int", value: int(-1), want: int64(-1)},
+		{name: "int8", value: int8(math.MinInt8), want: int64(math.MinInt8)},
+		{name: "int16", value: int16(math.MaxInt16), want: int64(math.MaxInt16)},
+		{name: "int32", value: int32(math.MinInt32), want: int64(math.MinInt32)},
+		{name: "int64", value: int64(math.MaxInt64), want: int64(math.MaxInt64)},
+		{name: "uint", value: uint(7), want: int64(7)},
+		{name: "uint8", value: uint8(math.MaxUint8), want: int64(math.MaxUint8)},
+		{name: "uint16", value: uint16(math.MaxUint16), want: int64(math.MaxUint16)},
+		{name: "uint32", value: uint32(math.MaxUint32), want: int64(math.MaxUint32)},
+		{name: "uint64", value: uint64(math.MaxInt64), want: int64(math.MaxInt64)},
+		{name: "uint64 overflow", value: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "uintptr", value: uintptr(42), want: int64(42)},
+		{name: "float32", value: float32(1.5), want: float64(1.5)},
+		{name: "float64", value: 2.5, want: 2.5},
+		{name: "bool", value: true, want: true},
+		{name: "strings", value: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "ints", value: []int{1, -2}, want: []int64{1, -2}},
+		{name: "int8s", value: []int8{1}, want: []int64{1}},
+		{name: "int16s", value: []int16{1}, want: []int64{1}},
+		{name: "int32s", value: []int32{1}, want: []int64{1}},
+		{name: "int64s", value: []int64{1}, want: []int64{1}},
+		{name: "float32s", value: []float32{0.5}, want: []float64{0.5}},
+		{name: "float64s", value: []float64{0.5}, want: []float64{0.5}},
+		{name: "bools", value: []bool{true}, want: []bool{true}},
+		{name: "other", value: struct{ A int }{1}, want: "{1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := genstrumenttest.NewTracer()
+			_, span := tracer.StartSpan(context.Background(), "op")
+			genstrument.NewWrapperConfig(genstrument.WithAttribute("key", tt.value)).SetAttributes(span)
+			span.EndSuccess(context.Background())
+			got, ok := tracer.Find("op").Attr("key")
+			if !ok {
+				t.Fatal("expected the attribute to be set")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
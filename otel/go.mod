@@ -0,0 +1,20 @@
+module github.com/justenwalker/genstrument/otel
+
+go 1.22
+
+require (
+	github.com/justenwalker/genstrument v0.0.0
+	go.opentelemetry.io/otel v1.30.0
+	go.opentelemetry.io/otel/sdk v1.30.0
+	go.opentelemetry.io/otel/trace v1.30.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.30.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+)
+
+replace github.com/justenwalker/genstrument => ../
@@ -0,0 +1,226 @@
+// Package otel implements genstrument.Tracer with OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/justenwalker/genstrument"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the default instrumentation scope name of the tracer.
+	ScopeName = "github.com/justenwalker/genstrument/otel"
+	// Version is the instrumentation scope version of the tracer.
+	Version = "0.1.0"
+)
+
+// Option configures a Tracer.
+type Option func(*config)
+
+type config struct {
+	scopeName    string
+	scopeVersion string
+	tracerOpts   []trace.TracerOption
+	startOpts    []trace.SpanStartOption
+	opStartOpts  func(operationName string) []trace.SpanStartOption
+}
+
+// WithScope sets the instrumentation scope name and version, instead of ScopeName and Version.
+func WithScope(name, version string) Option {
+	return func(c *config) {
+		c.scopeName = name
+		c.scopeVersion = version
+	}
+}
+
+// WithTracerOptions adds options used to create the trace.Tracer, like trace.WithSchemaURL.
+func WithTracerOptions(opts ...trace.TracerOption) Option {
+	return func(c *config) {
+		c.tracerOpts = append(c.tracerOpts, opts...)
+	}
+}
+
+// WithSpanKind sets the kind of every span. Spans are internal by default.
+func WithSpanKind(kind trace.SpanKind) Option {
+	return WithSpanStartOptions(trace.WithSpanKind(kind))
+}
+
+// WithSpanStartOptions adds options used to start every span.
+func WithSpanStartOptions(opts ...trace.SpanStartOption) Option {
+	return func(c *config) {
+		c.startOpts = append(c.startOpts, opts...)
+	}
+}
+
+// WithOperationStartOptions sets a function returning additional options used to start the spans of an operation,
+// like a span kind for the operations of a client.
+func WithOperationStartOptions(fn func(operationName string) []trace.SpanStartOption) Option {
+	return func(c *config) {
+		c.opStartOpts = fn
+	}
+}
+
+type startOptionsKey struct{}
+
+// ContextWithSpanStartOptions returns a context with options used to start the next span started from it,
+// like trace.WithLinks. They are applied after the options of the Tracer.
+// They do not apply to the spans started from that span's context.
+func ContextWithSpanStartOptions(ctx context.Context, opts ...trace.SpanStartOption) context.Context {
+	return context.WithValue(ctx, startOptionsKey{}, opts)
+}
+
+// Tracer is a genstrument.Tracer starting OpenTelemetry spans.
+//
+// EndError sets the status of the span to codes.Error and records the error as an exception event.
+// EndPanic does the same, adding the stack trace to the event.
+// EndCanceled records the error as an exception event, leaving the status unset,
+// since the caller, not the operation, ended the call.
+// EndSuccess leaves the status unset, as recommended for instrumentation libraries.
+// Attributes are buffered and set on the span when it ends.
+type Tracer struct {
+	tracer      trace.Tracer
+	startOpts   []trace.SpanStartOption
+	opStartOpts func(operationName string) []trace.SpanStartOption
+}
+
+// NewTracer returns a Tracer starting spans with a tracer from tp.
+func NewTracer(tp trace.TracerProvider, opts ...Option) *Tracer {
+	c := config{
+		scopeName:    ScopeName,
+		scopeVersion: Version,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	tracerOpts := append([]trace.TracerOption{trace.WithInstrumentationVersion(c.scopeVersion)}, c.tracerOpts...)
+	return &Tracer{
+		tracer:      tp.Tracer(c.scopeName, tracerOpts...),
+		startOpts:   c.startOpts,
+		opStartOpts: c.opStartOpts,
+	}
+}
+
+func (t *Tracer) StartSpan(ctx context.Context, operationName string) (context.Context, genstrument.Span) {
+	opts := t.startOpts
+	if t.opStartOpts != nil {
+		if opOpts := t.opStartOpts(operationName); len(opOpts) > 0 {
+			opts = append(opts[:len(opts):len(opts)], opOpts...)
+		}
+	}
+	if ctxOpts, ok := ctx.Value(startOptionsKey{}).([]trace.SpanStartOption); ok {
+		opts = append(opts[:len(opts):len(opts)], ctxOpts...)
+		ctx = context.WithValue(ctx, startOptionsKey{}, nil)
+	}
+	ctx, s := t.tracer.Start(ctx, operationName, opts...)
+	sp := &span{span: s}
+	sp.attrs = sp.attrBuf[:0]
+	sp.setters = sp.setterBuf[:0]
+	return ctx, sp
+}
+
+type span struct {
+	span      trace.Span
+	attrs     []attribute.KeyValue
+	setters   []keyValue
+	attrBuf   [8]attribute.KeyValue
+	setterBuf [8]keyValue
+}
+
+func (s *span) Attribute(key string) genstrument.AttributeSetter {
+	s.setters = append(s.setters, keyValue{key: key, span: s})
+	return &s.setters[len(s.setters)-1]
+}
+
+func (s *span) EndSuccess(_ context.Context) {
+	s.end()
+}
+
+func (s *span) EndError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+	s.end()
+}
+
+// EndPanic records the panic as an exception event with its stack trace, and sets the status to codes.Error.
+func (s *span) EndPanic(v any, stack []byte) {
+	err := &genstrument.PanicError{Value: v, Stack: stack}
+	s.span.RecordError(err, trace.WithAttributes(attribute.String("exception.stacktrace", string(stack))))
+	s.span.SetStatus(codes.Error, err.Error())
+	s.end()
+}
+
+// EndCanceled records the error as an exception event without setting the status to codes.Error.
+func (s *span) EndCanceled(_ context.Context, err error) {
+	s.span.RecordError(err)
+	s.end()
+}
+
+func (s *span) end() {
+	if len(s.attrs) > 0 {
+		s.span.SetAttributes(s.attrs...)
+	}
+	s.span.End()
+}
+
+type keyValue struct {
+	key  string
+	span *span
+}
+
+func (k *keyValue) set(kv attribute.KeyValue) {
+	k.span.attrs = append(k.span.attrs, kv)
+}
+
+func (k *keyValue) Attribute(key string) genstrument.AttributeSetter {
+	return k.span.Attribute(strings.Join([]string{k.key, key}, "."))
+}
+
+func (k *keyValue) Error(err error) {
+	k.set(attribute.String(k.key, err.Error()))
+}
+
+func (k *keyValue) Stringer(v fmt.Stringer) {
+	k.set(attribute.Stringer(k.key, v))
+}
+
+func (k *keyValue) String(v string) {
+	k.set(attribute.String(k.key, v))
+}
+
+func (k *keyValue) Int64(v int64) {
+	k.set(attribute.Int64(k.key, v))
+}
+
+func (k *keyValue) Bool(v bool) {
+	k.set(attribute.Bool(k.key, v))
+}
+
+func (k *keyValue) Float64(v float64) {
+	k.set(attribute.Float64(k.key, v))
+}
+
+func (k *keyValue) StringSlice(v []string) {
+	k.set(attribute.StringSlice(k.key, v))
+}
+
+func (k *keyValue) BoolSlice(v []bool) {
+	k.set(attribute.BoolSlice(k.key, v))
+}
+
+func (k *keyValue) Float64Slice(v []float64) {
+	k.set(attribute.Float64Slice(k.key, v))
+}
+
+func (k *keyValue) Int64Slice(v []int64) {
+	k.set(attribute.Int64Slice(k.key, v))
+}
+
+var _ genstrument.Tracer = (*Tracer)(nil)
+var _ genstrument.PanicSpan = (*span)(nil)
+var _ genstrument.CancelSpan = (*span)(nil)
+var _ genstrument.AttributeSetter = (*keyValue)(nil)
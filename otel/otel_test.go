@@ -0,0 +1,168 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/justenwalker/genstrument"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	sr := tracetest.NewSpanRecorder()
+	return sr, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+}
+
+func TestTracerScope(t *testing.T) {
+	sr, tp := newRecorder()
+	_, span := NewTracer(tp).StartSpan(context.Background(), "op")
+	span.EndSuccess(context.Background())
+	_, span = NewTracer(tp, WithScope("custom", "1.2.3")).StartSpan(context.Background(), "op")
+	span.EndSuccess(context.Background())
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if s := spans[0].InstrumentationScope(); s.Name != ScopeName || s.Version != Version {
+		t.Errorf("expected default scope %s %s, got %s %s", ScopeName, Version, s.Name, s.Version)
+	}
+	if s := spans[1].InstrumentationScope(); s.Name != "custom" || s.Version != "1.2.3" {
+		t.Errorf("expected scope custom 1.2.3, got %s %s", s.Name, s.Version)
+	}
+}
+
+func TestTracerEndError(t *testing.T) {
+	sr, tp := newRecorder()
+	_, span := NewTracer(tp).StartSpan(context.Background(), "op")
+	err := errors.New("failed")
+	genstrument.SetErrorAttribute(err, span.Attribute("err"))
+	span.EndError(err)
+
+	s := sr.Ended()[0]
+	if s.Status().Code != codes.Error || s.Status().Description != "failed" {
+		t.Errorf("expected error status, got %v", s.Status())
+	}
+	var exceptions int
+	for _, e := range s.Events() {
+		if e.Name == "exception" {
+			exceptions++
+		}
+	}
+	if exceptions != 1 {
+		t.Errorf("expected 1 exception event, got %d", exceptions)
+	}
+	want := []attribute.KeyValue{attribute.String("err", "failed")}
+	if got := s.Attributes(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("expected attributes %v, got %v", want, got)
+	}
+}
+
+func TestTracerEndSuccess(t *testing.T) {
+	sr, tp := newRecorder()
+	ctx, parent := NewTracer(tp).StartSpan(context.Background(), "parent")
+	_, span := NewTracer(tp).StartSpan(ctx, "child")
+	genstrument.SetStringAttribute("v", span.Attribute("str"))
+	genstrument.SetIntAttribute(42, span.Attribute("nested").Attribute("int"))
+	span.Attribute("slice").StringSlice([]string{"a", "b"})
+	span.EndSuccess(ctx)
+	parent.EndSuccess(ctx)
+
+	child, p := sr.Ended()[0], sr.Ended()[1]
+	if child.Status().Code != codes.Unset {
+		t.Errorf("expected unset status, got %v", child.Status())
+	}
+	if child.Parent().SpanID() != p.SpanContext().SpanID() {
+		t.Errorf("expected child of %v, got parent %v", p.SpanContext().SpanID(), child.Parent().SpanID())
+	}
+	want := []attribute.KeyValue{
+		attribute.String("str", "v"),
+		attribute.Int64("nested.int", 42),
+		attribute.StringSlice("slice", []string{"a", "b"}),
+	}
+	got := child.Attributes()
+	if len(got) != len(want) {
+		t.Fatalf("expected attributes %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || got[i].Value.Emit() != want[i].Value.Emit() {
+			t.Errorf("attribute %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestTracerStartOptions(t *testing.T) {
+	sr, tp := newRecorder()
+	_, linked := NewTracer(tp).StartSpan(context.Background(), "linked")
+	linked.EndSuccess(context.Background())
+	link := trace.Link{SpanContext: sr.Ended()[0].SpanContext()}
+
+	tracer := NewTracer(tp,
+		WithSpanKind(trace.SpanKindServer),
+		WithOperationStartOptions(func(op string) []trace.SpanStartOption {
+			if op == "client" {
+				return []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}
+			}
+			return nil
+		}),
+	)
+	ctx := ContextWithSpanStartOptions(context.Background(), trace.WithLinks(link))
+	ctx, server := tracer.StartSpan(ctx, "server")
+	_, client := tracer.StartSpan(ctx, "client")
+	client.EndSuccess(ctx)
+	server.EndSuccess(ctx)
+
+	c, s := sr.Ended()[1], sr.Ended()[2]
+	if s.SpanKind() != trace.SpanKindServer || c.SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected server and client spans, got %v and %v", s.SpanKind(), c.SpanKind())
+	}
+	if len(s.Links()) != 1 || s.Links()[0].SpanContext.SpanID() != link.SpanContext.SpanID() {
+		t.Errorf("expected server span link to %v, got %v", link.SpanContext.SpanID(), s.Links())
+	}
+	if len(c.Links()) != 0 {
+		t.Errorf("expected context options to apply to one span, got client links %v", c.Links())
+	}
+}
+
+func TestTracerEndPanic(t *testing.T) {
+	sr, tp := newRecorder()
+	_, span := NewTracer(tp).StartSpan(context.Background(), "op")
+	span.(genstrument.PanicSpan).EndPanic("boom", []byte("stack"))
+
+	s := sr.Ended()[0]
+	if s.Status().Code != codes.Error || s.Status().Description != "panic: boom" {
+		t.Errorf("expected error status, got %v", s.Status())
+	}
+	if len(s.Events()) != 1 {
+		t.Fatalf("expected 1 exception event, got %v", s.Events())
+	}
+	var stack string
+	for _, kv := range s.Events()[0].Attributes {
+		if kv.Key == "exception.stacktrace" {
+			stack = kv.Value.AsString()
+		}
+	}
+	if stack != "stack" {
+		t.Errorf("expected exception.stacktrace attribute, got %v", s.Events()[0].Attributes)
+	}
+}
+
+func TestTracerEndCanceled(t *testing.T) {
+	sr, tp := newRecorder()
+	ctx := context.Background()
+	_, span := NewTracer(tp).StartSpan(ctx, "op")
+	genstrument.EndClassified(ctx, span, context.Canceled, genstrument.ErrorClassCanceled)
+
+	s := sr.Ended()[0]
+	if s.Status().Code != codes.Unset {
+		t.Errorf("expected unset status, got %v", s.Status())
+	}
+	if len(s.Events()) != 1 || s.Events()[0].Name != "exception" {
+		t.Errorf("expected 1 exception event, got %v", s.Events())
+	}
+}
@@ -0,0 +1,45 @@
+package genstrument
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicSpan is implemented by a Span that can record a panic of the traced call.
+type PanicSpan interface {
+	Span
+	EndPanic(v any, stack []byte)
+}
+
+// PanicError is the error of a panic, recorded or returned by wrappers instead of re-panicking.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// NewPanicError returns the PanicError of the panic value v, with the stack of the calling goroutine.
+// It is called by wrappers in the deferred function recovering v, so that generated code does not
+// import runtime/debug, whose package name may be shadowed by a parameter.
+func NewPanicError(v any) *PanicError {
+	return &PanicError{Value: v, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// EndPanic ends span after the traced call panicked with v.
+// If span is not a PanicSpan, it ends with a *PanicError.
+func EndPanic(span Span, v any, stack []byte) {
+	if ps, ok := span.(PanicSpan); ok {
+		ps.EndPanic(v, stack)
+		return
+	}
+	span.EndError(&PanicError{Value: v, Stack: stack})
+}
@@ -1,5 +1,14 @@
 package genstrument
 
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
 func SetStringAttribute[S ~string](str S, setter AttributeSetter) {
 	setter.String(string(str))
 }
@@ -8,6 +17,16 @@ func SetIntAttribute[I ~int | ~int8 | ~int16 | ~int32 | ~int64](i I, setter Attr
 	setter.Int64(int64(i))
 }
 
+// SetUintAttribute sets unsigned integers as Int64 attributes,
+// or as decimal String attributes when they overflow an int64.
+func SetUintAttribute[U ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](u U, setter AttributeSetter) {
+	if uint64(u) > math.MaxInt64 {
+		setter.String(strconv.FormatUint(uint64(u), 10))
+		return
+	}
+	setter.Int64(int64(u))
+}
+
 func SetBoolAttribute[B ~bool](b B, setter AttributeSetter) {
 	setter.Bool(bool(b))
 }
@@ -22,3 +41,158 @@ func SetErrorAttribute(err error, setter AttributeSetter) {
 	}
 	setter.Error(err)
 }
+
+// SetStringerAttribute sets v as a Stringer attribute, unless it is nil.
+func SetStringerAttribute(v fmt.Stringer, setter AttributeSetter) {
+	if v == nil {
+		return
+	}
+	setter.Stringer(v)
+}
+
+// SetTimeAttribute sets t as a String attribute formatted with time.RFC3339Nano.
+func SetTimeAttribute(t time.Time, setter AttributeSetter) {
+	setter.String(t.Format(time.RFC3339Nano))
+}
+
+// SetDurationAttribute sets d as a String attribute, like "1m30s".
+func SetDurationAttribute(d time.Duration, setter AttributeSetter) {
+	setter.String(d.String())
+}
+
+// SetBytesAttribute sets b as a String attribute encoded with base64.StdEncoding.
+func SetBytesAttribute[B ~[]byte](b B, setter AttributeSetter) {
+	setter.String(base64.StdEncoding.EncodeToString(b))
+}
+
+func SetStringSliceAttribute[S ~[]E, E ~string](s S, setter AttributeSetter) {
+	v := make([]string, len(s))
+	for i, e := range s {
+		v[i] = string(e)
+	}
+	setter.StringSlice(v)
+}
+
+func SetIntSliceAttribute[S ~[]E, E ~int | ~int8 | ~int16 | ~int32 | ~int64](s S, setter AttributeSetter) {
+	v := make([]int64, len(s))
+	for i, e := range s {
+		v[i] = int64(e)
+	}
+	setter.Int64Slice(v)
+}
+
+func SetBoolSliceAttribute[S ~[]E, E ~bool](s S, setter AttributeSetter) {
+	v := make([]bool, len(s))
+	for i, e := range s {
+		v[i] = bool(e)
+	}
+	setter.BoolSlice(v)
+}
+
+func SetFloatSliceAttribute[S ~[]E, E ~float32 | ~float64](s S, setter AttributeSetter) {
+	v := make([]float64, len(s))
+	for i, e := range s {
+		v[i] = float64(e)
+	}
+	setter.Float64Slice(v)
+}
+
+// The pointer setters set the value pointed to, and no attribute if the pointer is nil.
+
+func SetStringPointerAttribute[S ~string](p *S, setter AttributeSetter) {
+	if p != nil {
+		SetStringAttribute(*p, setter)
+	}
+}
+
+func SetIntPointerAttribute[I ~int | ~int8 | ~int16 | ~int32 | ~int64](p *I, setter AttributeSetter) {
+	if p != nil {
+		SetIntAttribute(*p, setter)
+	}
+}
+
+func SetUintPointerAttribute[U ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](p *U, setter AttributeSetter) {
+	if p != nil {
+		SetUintAttribute(*p, setter)
+	}
+}
+
+func SetBoolPointerAttribute[B ~bool](p *B, setter AttributeSetter) {
+	if p != nil {
+		SetBoolAttribute(*p, setter)
+	}
+}
+
+func SetFloatPointerAttribute[F ~float32 | ~float64](p *F, setter AttributeSetter) {
+	if p != nil {
+		SetFloatAttribute(*p, setter)
+	}
+}
+
+func SetTimePointerAttribute(p *time.Time, setter AttributeSetter) {
+	if p != nil {
+		SetTimeAttribute(*p, setter)
+	}
+}
+
+func SetDurationPointerAttribute(p *time.Duration, setter AttributeSetter) {
+	if p != nil {
+		SetDurationAttribute(*p, setter)
+	}
+}
+
+func SetErrorPointerAttribute[T any, PT interface {
+	*T
+	error
+}](p PT, setter AttributeSetter) {
+	if p != nil {
+		setter.Error(p)
+	}
+}
+
+func SetStringerPointerAttribute[T any, PT interface {
+	*T
+	fmt.Stringer
+}](p PT, setter AttributeSetter) {
+	if p != nil {
+		setter.Stringer(p)
+	}
+}
+
+// AttributeMarshaler is implemented by types setting their own attributes.
+// Attribute directives on arguments of such types, or of pointers to them, use it
+// when they do not name a setter.
+type AttributeMarshaler interface {
+	MarshalAttributes(setter AttributeSetter)
+}
+
+// SetMarshalerAttribute sets the attributes of m with its MarshalAttributes method, unless m is nil
+// or a nil pointer, whose value receiver method would panic.
+func SetMarshalerAttribute[M AttributeMarshaler](m M, setter AttributeSetter) {
+	if any(m) == nil {
+		return
+	}
+	if v := reflect.ValueOf(m); v.Kind() == reflect.Pointer && v.IsNil() {
+		return
+	}
+	m.MarshalAttributes(setter)
+}
+
+// SetMarshalerPointerAttribute sets the attributes of p with its MarshalAttributes method, unless p is nil.
+func SetMarshalerPointerAttribute[T any, PT interface {
+	*T
+	AttributeMarshaler
+}](p PT, setter AttributeSetter) {
+	if p == nil {
+		return
+	}
+	p.MarshalAttributes(setter)
+}
+
+// SetMarshalerAddrAttribute sets the attributes of v with the MarshalAttributes method of its pointer type.
+func SetMarshalerAddrAttribute[T any, PT interface {
+	*T
+	AttributeMarshaler
+}](v T, setter AttributeSetter) {
+	PT(&v).MarshalAttributes(setter)
+}
@@ -0,0 +1,45 @@
+package genstrument_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/justenwalker/genstrument"
+	"github.com/justenwalker/genstrument/genstrumenttest"
+)
+
+type user struct {
+	ID string
+}
+
+func (u user) MarshalAttributes(setter genstrument.AttributeSetter) {
+	setter.Attribute("id").String(u.ID)
+}
+
+func TestSetMarshalerAttribute(t *testing.T) {
+	tests := []struct {
+		name  string
+		value genstrument.AttributeMarshaler
+		set   bool
+	}{
+		{name: "value", value: user{ID: "u1"}, set: true},
+		{name: "pointer", value: &user{ID: "u1"}, set: true},
+		{name: "nil pointer", value: (*user)(nil)},
+		{name: "nil", value: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := genstrumenttest.NewTracer()
+			_, span := tracer.StartSpan(context.Background(), "op")
+			genstrument.SetMarshalerAttribute(tt.value, span.Attribute("user"))
+			span.EndSuccess(context.Background())
+			got, ok := tracer.Find("op").Attr("user.id")
+			if ok != tt.set {
+				t.Fatalf("expected the attribute to be set: %v, got %v", tt.set, got)
+			}
+			if ok && got != "u1" {
+				t.Errorf("expected u1, got %#v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+//go:build go1.21
+
+// Package slogtracer logs genstrument spans and calls with log/slog.
+package slogtracer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/justenwalker/genstrument"
+)
+
+// Tracer is a genstrument.Tracer that logs every span when it ends.
+// Spans started from a context carrying another span are logged with its id as their parent id.
+type Tracer struct {
+	Logger *slog.Logger
+}
+
+var spanIDs atomic.Uint64
+
+type spanKey struct{}
+
+type span struct {
+	// ctx is the context of the span, logged with by the methods ending it without a context.
+	ctx      context.Context
+	logger   *slog.Logger
+	op       string
+	id       uint64
+	parentID uint64
+	start    time.Time
+	attrs    Attributes
+}
+
+func (t *Tracer) StartSpan(ctx context.Context, operationName string) (context.Context, genstrument.Span) {
+	s := &span{
+		logger: t.Logger,
+		op:     operationName,
+		id:     spanIDs.Add(1),
+		start:  time.Now(),
+	}
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*span); ok {
+		s.parentID = parent.id
+	}
+	s.ctx = context.WithValue(ctx, spanKey{}, s)
+	return s.ctx, s
+}
+
+func (s *span) Attribute(key string) genstrument.AttributeSetter {
+	return s.attrs.Attribute(key)
+}
+
+func (s *span) EndSuccess(ctx context.Context) {
+	s.end(ctx, nil)
+}
+
+func (s *span) EndError(err error) {
+	s.end(s.ctx, err)
+}
+
+// EndCanceled logs the span with its error at the info level, marked as canceled.
+func (s *span) EndCanceled(ctx context.Context, err error) {
+	s.endClassified(ctx, err, genstrument.ErrorClassCanceled)
+}
+
+// EndPanic logs the span with the panic as its error, and the stack.
+func (s *span) EndPanic(v any, stack []byte) {
+	s.end(s.ctx, &genstrument.PanicError{Value: v, Stack: stack}, slog.String("stack", string(stack)))
+}
+
+func (s *span) end(ctx context.Context, err error, extra ...slog.Attr) {
+	s.endClassified(ctx, err, genstrument.ErrorClassFailed, extra...)
+}
+
+func (s *span) endClassified(ctx context.Context, err error, class genstrument.ErrorClass, extra ...slog.Attr) {
+	attrs := []slog.Attr{
+		slog.String("op", s.op),
+		slog.Uint64("span_id", s.id),
+	}
+	attrs = append(attrs, extra...)
+	if s.parentID != 0 {
+		attrs = append(attrs, slog.Uint64("parent_id", s.parentID))
+	}
+	log(ctx, s.logger, "span ended", attrs, s.start, &s.attrs, err, class)
+}
+
+// Call logs a call to a function wrapped with the log directive.
+// The generated wrapper sets the input attributes, calls Start, calls the function,
+// sets the result attributes, and then calls End, or EndClassified if the function has an errclass directive.
+type Call struct {
+	logger *slog.Logger
+	op     string
+	start  time.Time
+	attrs  Attributes
+}
+
+// NewCall returns a Call to operationName logged to logger, or slog.Default() if it is nil.
+func NewCall(logger *slog.Logger, operationName string) *Call {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Call{logger: logger, op: operationName}
+}
+
+func (c *Call) Attribute(key string) genstrument.AttributeSetter {
+	return c.attrs.Attribute(key)
+}
+
+// Start logs the start of the call with the attributes set so far.
+func (c *Call) Start(ctx context.Context) {
+	c.start = time.Now()
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "call started", slog.String("op", c.op), slog.Group("attrs", c.attrs.anyAttrs()...))
+}
+
+// End logs the end of the call with its duration, its error if not nil, and all attributes.
+func (c *Call) End(ctx context.Context, err error) {
+	c.EndClassified(ctx, err, genstrument.ErrorClassFailed)
+}
+
+// EndClassified logs the end of the call like End, with its error of the class.
+// Only failed errors are logged at the error level; canceled errors are marked as canceled.
+func (c *Call) EndClassified(ctx context.Context, err error, class genstrument.ErrorClass) {
+	log(ctx, c.logger, "call ended", []slog.Attr{slog.String("op", c.op)}, c.start, &c.attrs, err, class)
+}
+
+func log(ctx context.Context, logger *slog.Logger, msg string, attrs []slog.Attr, start time.Time, a *Attributes, err error, class genstrument.ErrorClass) {
+	level := slog.LevelInfo
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+	if err != nil {
+		if class == genstrument.ErrorClassFailed {
+			level = slog.LevelError
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+		if class == genstrument.ErrorClassCanceled {
+			attrs = append(attrs, slog.Bool("canceled", true))
+		}
+	}
+	if len(a.attrs) > 0 {
+		attrs = append(attrs, slog.Group("attrs", a.anyAttrs()...))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// Attributes collects genstrument attributes as slog attributes.
+type Attributes struct {
+	attrs []slog.Attr
+}
+
+// Attribute returns the setter of the attribute key.
+func (a *Attributes) Attribute(key string) genstrument.AttributeSetter {
+	return &keyValue{key: key, attrs: a}
+}
+
+// Attrs returns the attributes set so far.
+func (a *Attributes) Attrs() []slog.Attr {
+	return a.attrs
+}
+
+func (a *Attributes) anyAttrs() []any {
+	args := make([]any, len(a.attrs))
+	for i, attr := range a.attrs {
+		args[i] = attr
+	}
+	return args
+}
+
+type keyValue struct {
+	key   string
+	attrs *Attributes
+}
+
+func (k *keyValue) add(v slog.Value) {
+	k.attrs.attrs = append(k.attrs.attrs, slog.Attr{Key: k.key, Value: v})
+}
+
+func (k *keyValue) Attribute(key string) genstrument.AttributeSetter {
+	return &keyValue{
+		key:   strings.Join([]string{k.key, key}, "."),
+		attrs: k.attrs,
+	}
+}
+
+func (k *keyValue) Error(err error) {
+	k.add(slog.StringValue(err.Error()))
+}
+
+func (k *keyValue) Stringer(v fmt.Stringer) {
+	k.add(slog.StringValue(v.String()))
+}
+
+func (k *keyValue) String(v string) {
+	k.add(slog.StringValue(v))
+}
+
+func (k *keyValue) Int64(v int64) {
+	k.add(slog.Int64Value(v))
+}
+
+func (k *keyValue) Bool(v bool) {
+	k.add(slog.BoolValue(v))
+}
+
+func (k *keyValue) Float64(v float64) {
+	k.add(slog.Float64Value(v))
+}
+
+func (k *keyValue) StringSlice(v []string) {
+	k.add(slog.AnyValue(v))
+}
+
+func (k *keyValue) BoolSlice(v []bool) {
+	k.add(slog.AnyValue(v))
+}
+
+func (k *keyValue) Float64Slice(v []float64) {
+	k.add(slog.AnyValue(v))
+}
+
+func (k *keyValue) Int64Slice(v []int64) {
+	k.add(slog.AnyValue(v))
+}
+
+var _ genstrument.Tracer = (*Tracer)(nil)
+var _ genstrument.PanicSpan = (*span)(nil)
+var _ genstrument.CancelSpan = (*span)(nil)
+var _ genstrument.AttributeSetter = (*keyValue)(nil)
@@ -0,0 +1,136 @@
+//go:build go1.21
+
+package slogtracer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/justenwalker/genstrument"
+)
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := &Tracer{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+	ctx, parent := tracer.StartSpan(context.Background(), "parent")
+	_, child := tracer.StartSpan(ctx, "child")
+	genstrument.SetStringAttribute("value", child.Attribute("key"))
+	child.EndError(errors.New("failed"))
+	parent.EndSuccess(ctx)
+
+	recs := records(t, &buf)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	c, p := recs[0], recs[1]
+	if c["op"] != "child" || c["level"] != "ERROR" || c["error"] != "failed" {
+		t.Errorf("unexpected child record: %v", c)
+	}
+	if attrs, _ := c["attrs"].(map[string]any); attrs["key"] != "value" {
+		t.Errorf("expected child attribute key=value, got %v", c["attrs"])
+	}
+	if p["op"] != "parent" || p["level"] != "INFO" || p["parent_id"] != nil {
+		t.Errorf("unexpected parent record: %v", p)
+	}
+	if c["parent_id"] != p["span_id"] {
+		t.Errorf("expected child parent_id %v to be the parent span_id %v", c["parent_id"], p["span_id"])
+	}
+}
+
+func TestCall(t *testing.T) {
+	var buf bytes.Buffer
+	call := NewCall(slog.New(slog.NewJSONHandler(&buf, nil)), "op")
+	genstrument.SetIntAttribute(1, call.Attribute("in"))
+	call.Start(context.Background())
+	call.Attribute("out").Attribute("ok").Bool(true)
+	call.End(context.Background(), nil)
+
+	recs := records(t, &buf)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	start, end := recs[0], recs[1]
+	if start["msg"] != "call started" || start["attrs"].(map[string]any)["in"] != 1.0 {
+		t.Errorf("unexpected start record: %v", start)
+	}
+	if end["msg"] != "call ended" || end["level"] != "INFO" || end["duration"] == nil {
+		t.Errorf("unexpected end record: %v", end)
+	}
+	if attrs := end["attrs"].(map[string]any); attrs["in"] != 1.0 || attrs["out.ok"] != true {
+		t.Errorf("unexpected end attributes: %v", attrs)
+	}
+}
+
+func TestEndClassified(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	_, span := (&Tracer{Logger: logger}).StartSpan(context.Background(), "span")
+	genstrument.EndClassified(context.Background(), span, context.Canceled, genstrument.ErrorClassCanceled)
+	call := NewCall(logger, "call")
+	call.Start(context.Background())
+	call.EndClassified(context.Background(), errors.New("not found"), genstrument.ErrorClassExpected)
+
+	recs := records(t, &buf)
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(recs))
+	}
+	s, end := recs[0], recs[2]
+	if s["level"] != "INFO" || s["error"] != "context canceled" || s["canceled"] != true {
+		t.Errorf("unexpected canceled span record: %v", s)
+	}
+	if end["level"] != "INFO" || end["error"] != "not found" || end["canceled"] != nil {
+		t.Errorf("unexpected expected error record: %v", end)
+	}
+}
+
+type requestIDKey struct{}
+
+// requestIDHandler adds the request id of the context to the records.
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestSpanContext(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := &Tracer{Logger: slog.New(requestIDHandler{slog.NewJSONHandler(&buf, nil)})}
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r1")
+	_, failed := tracer.StartSpan(ctx, "failed")
+	failed.EndError(errors.New("failed"))
+	_, panicked := tracer.StartSpan(ctx, "panicked")
+	genstrument.EndPanic(panicked, "boom", nil)
+
+	recs := records(t, &buf)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	for _, rec := range recs {
+		if rec["request_id"] != "r1" {
+			t.Errorf("expected the record of %v to be logged with the span context, got %v", rec["op"], rec)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package genstrument
+
+import "reflect"
+
+// Unwrapper is implemented by the generated wrappers.
+// GenstrumentUnwrap returns the wrapped value; the method is not named Unwrap
+// so that it cannot collide with a method of the wrapped interface.
+type Unwrapper interface {
+	GenstrumentUnwrap() any
+}
+
+// Unwrap returns v with every genstrument wrapper around it removed.
+func Unwrap[T any](v T) T {
+	for {
+		u, ok := any(v).(Unwrapper)
+		if !ok {
+			return v
+		}
+		wrapped, ok := u.GenstrumentUnwrap().(T)
+		if !ok {
+			return v
+		}
+		v = wrapped
+	}
+}
+
+// SameInstrument reports whether the tracers, meters or loggers a and b are the same.
+// Constructors use it to return a value already wrapped with the same instruments as-is.
+// Values of a type that is not comparable are never the same, nor are values of a comparable
+// type holding a value that is not, like a struct with an interface field holding a slice.
+func SameInstrument(a, b any) (same bool) {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || (t != nil && !t.Comparable()) {
+		return false
+	}
+	defer func() {
+		if recover() != nil { // comparing a value that is not comparable
+			same = false
+		}
+	}()
+	return a == b
+}